@@ -25,27 +25,236 @@ type SecretKeyRefSpec struct {
 	Key  string `json:"key,omitempty"`
 }
 
+type SSHKeyRefSpec struct {
+	// Name of the Secret containing the private key
+	Name string `json:"name,omitempty"`
+	// Key inside the Secret containing the private key
+	Key string `json:"key,omitempty"`
+	// PassphraseKey is the key inside the Secret containing the passphrase of the private key, if it has one
+	PassphraseKey string `json:"passphraseKey,omitempty"`
+}
+
+type ResourceKeyRefSpec struct {
+	// Kind of the referenced resource, either Secret or ConfigMap. Defaults to Secret.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the referenced resource
+	Name string `json:"name,omitempty"`
+	// Key inside the referenced resource
+	Key string `json:"key,omitempty"`
+}
+
+type KeyMappingSpec struct {
+	// Key inside the Secret
+	Key string `json:"key"`
+	// Path of the gopass entry written to the key
+	Path string `json:"path"`
+	// Field of the body of the entry written to the key instead of its password, e.g. username
+	Field string `json:"field,omitempty"`
+}
+
+type RegistryCredentialsSpec struct {
+	// Server of the registry, e.g. registry.example.com
+	Server string `json:"server"`
+	// Path of the gopass entry whose password is used to log into the registry
+	Path string `json:"path"`
+	// UsernameField is the field of the body of the entry containing the username, defaults to username
+	UsernameField string `json:"usernameField,omitempty"`
+}
+
+type SecretTargetSpec struct {
+	// Name of the Secret to create in the namespace of the GopassRepository
+	Name string `json:"name"`
+	// Prefix selects all entries below the given path
+	Prefix string `json:"prefix,omitempty"`
+	// Include contains glob patterns of the entries written to this Secret
+	Include []string `json:"include,omitempty"`
+	// Data explicitly maps entries to keys of the Secret
+	Data []KeyMappingSpec `json:"data,omitempty"`
+	// IncludeFields additionally writes the fields of the body of selected entries to keys named <key>.<field>
+	IncludeFields bool `json:"includeFields,omitempty"`
+	// Templates maps keys of the Secret to Go templates rendered from entries
+	Templates map[string]string `json:"templates,omitempty"`
+	// Type of the Secret. The Secret has to contain the keys required by the type.
+	// +kubebuilder:validation:Enum=Opaque;kubernetes.io/dockerconfigjson;kubernetes.io/tls;kubernetes.io/basic-auth;kubernetes.io/ssh-auth
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Registries are written to the key .dockerconfigjson of a Secret of type kubernetes.io/dockerconfigjson
+	Registries []RegistryCredentialsSpec `json:"registries,omitempty"`
+	// Kind of the resource the entries are written to. Secret is the default, ConfigMap is meant for non-sensitive entries.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Labels added to the Secret in addition to SecretLabels
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the Secret in addition to SecretAnnotations
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type MountSpec struct {
+	// MountPoint is the path below which the entries of the repository appear, e.g. shared
+	MountPoint string `json:"mountPoint"`
+	// RepositoryUrl points to the URL of the mounted repository
+	RepositoryURL string `json:"repositoryUrl"`
+	// Ref is the branch, tag or commit to sync from. Without it the default branch is synced.
+	Ref string `json:"ref,omitempty"`
+	// UserName used to authenticate with. Without UserName, SecretKeyRef and SSHKeyRef the credentials of the GopassRepository are used.
+	UserName string `json:"userName,omitempty"`
+	// SecretKeyRef references the Secret to be used to authenticate
+	SecretKeyRef *SecretKeyRefSpec `json:"secretKeyRef,omitempty"`
+	// SSHKeyRef references the Secret containing the SSH private key to be used to authenticate
+	SSHKeyRef *SSHKeyRefSpec `json:"sshKeyRef,omitempty"`
+}
+
 // GopassRepositorySpec defines the desired state of GopassRepository
 type GopassRepositorySpec struct {
 	// RepositoryUrl points to the URL of the repository
 	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// Ref is the branch, tag or commit to sync from. Without it the default branch is synced.
+	Ref string `json:"ref,omitempty"`
+	// StorePath is the directory of the password store within the repository. It has to contain a .gpg-id. Without it the
+	// root of the repository is used.
+	StorePath string `json:"storePath,omitempty"`
 	// RefreshInterval denotes how often the repository should be updated
 	RefreshInterval string `json:"refreshInterval,omitempty"`
 	// UserName used to authenticate authenticate with
 	UserName string `json:"userName,omitempty"`
 	// SecretKeyRef references the Secret to be used to authenticate
 	SecretKeyRef SecretKeyRefSpec `json:"secretKeyRef,omitempty"`
-	GpgKeyRef    SecretKeyRefSpec `json:"gpgKeyRef,omitempty"`
+	// SSHKeyRef references the Secret containing the SSH private key to be used to authenticate
+	SSHKeyRef *SSHKeyRefSpec `json:"sshKeyRef,omitempty"`
+	// CABundleRef references a Secret or ConfigMap containing the CA certificates used to verify an HTTPS repository in
+	// addition to the system certificates
+	CABundleRef *ResourceKeyRefSpec `json:"caBundleRef,omitempty"`
+	// KnownHostsRef references a Secret or ConfigMap containing the known_hosts used to verify the host key of an SSH repository
+	KnownHostsRef *ResourceKeyRefSpec `json:"knownHostsRef,omitempty"`
+	// InsecureIgnoreHostKey disables the verification of the host key of an SSH repository if no KnownHostsRef is given
+	InsecureIgnoreHostKey bool             `json:"insecureIgnoreHostKey,omitempty"`
+	GpgKeyRef             SecretKeyRefSpec `json:"gpgKeyRef,omitempty"`
+	// GpgPassphraseRef references the Secret containing the passphrase of the GPG key, if it has one
+	GpgPassphraseRef *SecretKeyRefSpec `json:"gpgPassphraseRef,omitempty"`
+	// Mounts are further repositories mounted into the store, like 'gopass mounts add' does. They are decrypted using the same GPG key.
+	Mounts []MountSpec `json:"mounts,omitempty"`
+	// Include contains glob patterns of the entries to sync, e.g. team-a/prod/**. Without patterns all entries are synced.
+	Include []string `json:"include,omitempty"`
+	// Exclude contains glob patterns of entries that are not synced, even if they are included
+	Exclude []string `json:"exclude,omitempty"`
+	// Secrets lists the Secrets the entries are written to. Without it all entries are written to a Secret named after the GopassRepository.
+	Secrets []SecretTargetSpec `json:"secrets,omitempty"`
+	// IncludeFields additionally writes the fields of the body of all entries to keys named <key>.<field>
+	IncludeFields bool `json:"includeFields,omitempty"`
+	// Templates maps keys of the Secret named after the GopassRepository to Go templates rendered from entries
+	Templates map[string]string `json:"templates,omitempty"`
+	// KeyNaming selects how names of entries are turned into keys of a Secret. Replace, the default, replaces all
+	// non-alphanumeric characters with '-', KubernetesLegal keeps '.', '_' and '-', Basename only uses the last path
+	// segment and StripPrefix removes KeyNamingPrefix or the prefix of the target Secret.
+	// +kubebuilder:validation:Enum=Replace;KubernetesLegal;Basename;StripPrefix
+	// +optional
+	KeyNaming string `json:"keyNaming,omitempty"`
+	// KeyNamingPrefix is the prefix removed from the names of entries by the StripPrefix strategy
+	KeyNamingPrefix string `json:"keyNamingPrefix,omitempty"`
+	// CollisionPolicy decides what happens if several entries result in the same key. Fail, the default, fails the sync.
+	// KeepFirst and KeepLast keep one of the entries and report the collision in the status.
+	// +kubebuilder:validation:Enum=Fail;KeepFirst;KeepLast
+	// +optional
+	CollisionPolicy string `json:"collisionPolicy,omitempty"`
+	// DeletionPolicy decides what happens to the created Secrets and ConfigMaps when the GopassRepository is deleted.
+	// Delete, the default, deletes them while Orphan leaves them in the cluster. Orphan also leaves Secrets and ConfigMaps
+	// that are no longer targeted in the cluster, without the labels identifying the GopassRepository.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// PrunePolicy decides what happens to keys whose entry disappeared from the repository. Prune, the default, removes
+	// them while Keep keeps their last value and reports them as stale in the status. Keep also keeps Secrets and
+	// ConfigMaps that are no longer targeted and lists them in the status.
+	// +kubebuilder:validation:Enum=Prune;Keep
+	// +optional
+	PrunePolicy string `json:"prunePolicy,omitempty"`
+	// SecretLabels are added to all Secrets and ConfigMaps created for the repository
+	SecretLabels map[string]string `json:"secretLabels,omitempty"`
+	// SecretAnnotations are added to all Secrets and ConfigMaps created for the repository
+	SecretAnnotations map[string]string `json:"secretAnnotations,omitempty"`
+}
+
+const (
+	// DeletionPolicyDelete deletes the created Secrets and ConfigMaps together with the GopassRepository
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyOrphan leaves the created Secrets and ConfigMaps in the cluster when the GopassRepository is deleted
+	DeletionPolicyOrphan = "Orphan"
+)
+
+const (
+	// ConditionReady is true when the last reconciliation synced all secrets successfully
+	ConditionReady = "Ready"
+	// ConditionServerAvailable is true when the repository server is deployed and reachable
+	ConditionServerAvailable = "ServerAvailable"
+	// ConditionRepositoryInitialized is true when the repository server cloned the repository and imported the GPG key
+	ConditionRepositoryInitialized = "RepositoryInitialized"
+	// ConditionSynced is true when the entries of the repository have been written to the cluster
+	ConditionSynced = "Synced"
+	// ConditionInsecureHostKey is true as a warning when the host key of the repository is not verified
+	ConditionInsecureHostKey = "InsecureHostKey"
+)
+
+type KeyCollisionStatus struct {
+	// Secret containing the colliding key
+	Secret string `json:"secret"`
+	// Key produced by several entries
+	Key string `json:"key"`
+	// Entries resulting in the same key
+	Entries []string `json:"entries"`
+}
+
+type TemplateErrorStatus struct {
+	// Secret containing the key of the template
+	Secret string `json:"secret"`
+	// Key the template is rendered to
+	Key string `json:"key"`
+	// Message describing why the template could not be rendered
+	Message string `json:"message"`
+}
+
+type StaleKeyStatus struct {
+	// Secret containing the stale key
+	Secret string `json:"secret"`
+	// Key whose entry is no longer part of the repository
+	Key string `json:"key"`
 }
 
 // GopassRepositoryStatus defines the observed state of GopassRepository
 type GopassRepositoryStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions represent the latest available observations of the state of the repository
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// RepositoryURL is the URL of the repository initialized on the repository server
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// LastSyncedCommit is the hash of the commit the entries were last synced from
+	LastSyncedCommit string `json:"lastSyncedCommit,omitempty"`
+	// LastSyncTime is the time of the last successful sync
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SyncedEntries is the number of entries written during the last successful sync
+	SyncedEntries int32 `json:"syncedEntries,omitempty"`
+	// Collisions lists the keys that were produced by several entries during the last successful sync
+	Collisions []KeyCollisionStatus `json:"collisions,omitempty"`
+	// TemplateErrors lists the templates that could not be rendered during the last successful sync
+	TemplateErrors []TemplateErrorStatus `json:"templateErrors,omitempty"`
+	// StaleKeys lists the keys kept by the prune policy Keep although their entry disappeared
+	StaleKeys []StaleKeyStatus `json:"staleKeys,omitempty"`
+	// UpdatedSecrets lists the Secrets and ConfigMaps written during the last successful sync
+	UpdatedSecrets []string `json:"updatedSecrets,omitempty"`
+	// UnchangedSecrets lists the Secrets and ConfigMaps that already contained the synced content
+	UnchangedSecrets []string `json:"unchangedSecrets,omitempty"`
+	// UntargetedSecrets lists the Secrets and ConfigMaps kept by the prune policy Keep although no target writes them
+	UntargetedSecrets []string `json:"untargetedSecrets,omitempty"`
+	// LastError contains the error of the last failed reconciliation
+	LastError string `json:"lastError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Commit",type="string",JSONPath=".status.lastSyncedCommit"
+// +kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
 
 // GopassRepository is the Schema for the gopassrepositories API
 type GopassRepository struct {
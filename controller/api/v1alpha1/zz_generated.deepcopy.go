@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -29,8 +30,8 @@ func (in *GopassRepository) DeepCopyInto(out *GopassRepository) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GopassRepository.
@@ -87,7 +88,72 @@ func (in *GopassRepositoryList) DeepCopyObject() runtime.Object {
 func (in *GopassRepositorySpec) DeepCopyInto(out *GopassRepositorySpec) {
 	*out = *in
 	out.SecretKeyRef = in.SecretKeyRef
+	if in.SSHKeyRef != nil {
+		in, out := &in.SSHKeyRef, &out.SSHKeyRef
+		*out = new(SSHKeyRefSpec)
+		**out = **in
+	}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(ResourceKeyRefSpec)
+		**out = **in
+	}
+	if in.KnownHostsRef != nil {
+		in, out := &in.KnownHostsRef, &out.KnownHostsRef
+		*out = new(ResourceKeyRefSpec)
+		**out = **in
+	}
 	out.GpgKeyRef = in.GpgKeyRef
+	if in.GpgPassphraseRef != nil {
+		in, out := &in.GpgPassphraseRef, &out.GpgPassphraseRef
+		*out = new(SecretKeyRefSpec)
+		**out = **in
+	}
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]MountSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]SecretTargetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretLabels != nil {
+		in, out := &in.SecretLabels, &out.SecretLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretAnnotations != nil {
+		in, out := &in.SecretAnnotations, &out.SecretAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GopassRepositorySpec.
@@ -103,6 +169,49 @@ func (in *GopassRepositorySpec) DeepCopy() *GopassRepositorySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GopassRepositoryStatus) DeepCopyInto(out *GopassRepositoryStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Collisions != nil {
+		in, out := &in.Collisions, &out.Collisions
+		*out = make([]KeyCollisionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TemplateErrors != nil {
+		in, out := &in.TemplateErrors, &out.TemplateErrors
+		*out = make([]TemplateErrorStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaleKeys != nil {
+		in, out := &in.StaleKeys, &out.StaleKeys
+		*out = make([]StaleKeyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdatedSecrets != nil {
+		in, out := &in.UpdatedSecrets, &out.UpdatedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnchangedSecrets != nil {
+		in, out := &in.UnchangedSecrets, &out.UnchangedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UntargetedSecrets != nil {
+		in, out := &in.UntargetedSecrets, &out.UntargetedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GopassRepositoryStatus.
@@ -115,6 +224,111 @@ func (in *GopassRepositoryStatus) DeepCopy() *GopassRepositoryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyCollisionStatus) DeepCopyInto(out *KeyCollisionStatus) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCollisionStatus.
+func (in *KeyCollisionStatus) DeepCopy() *KeyCollisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyCollisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyMappingSpec) DeepCopyInto(out *KeyMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyMappingSpec.
+func (in *KeyMappingSpec) DeepCopy() *KeyMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountSpec) DeepCopyInto(out *MountSpec) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyRefSpec)
+		**out = **in
+	}
+	if in.SSHKeyRef != nil {
+		in, out := &in.SSHKeyRef, &out.SSHKeyRef
+		*out = new(SSHKeyRefSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountSpec.
+func (in *MountSpec) DeepCopy() *MountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCredentialsSpec) DeepCopyInto(out *RegistryCredentialsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCredentialsSpec.
+func (in *RegistryCredentialsSpec) DeepCopy() *RegistryCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceKeyRefSpec) DeepCopyInto(out *ResourceKeyRefSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceKeyRefSpec.
+func (in *ResourceKeyRefSpec) DeepCopy() *ResourceKeyRefSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceKeyRefSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeyRefSpec) DeepCopyInto(out *SSHKeyRefSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeyRefSpec.
+func (in *SSHKeyRefSpec) DeepCopy() *SSHKeyRefSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeyRefSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeyRefSpec) DeepCopyInto(out *SecretKeyRefSpec) {
 	*out = *in
@@ -129,3 +343,84 @@ func (in *SecretKeyRefSpec) DeepCopy() *SecretKeyRefSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretTargetSpec) DeepCopyInto(out *SecretTargetSpec) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]KeyMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]RegistryCredentialsSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTargetSpec.
+func (in *SecretTargetSpec) DeepCopy() *SecretTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleKeyStatus) DeepCopyInto(out *StaleKeyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleKeyStatus.
+func (in *StaleKeyStatus) DeepCopy() *StaleKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateErrorStatus) DeepCopyInto(out *TemplateErrorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateErrorStatus.
+func (in *TemplateErrorStatus) DeepCopy() *TemplateErrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateErrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -4,17 +4,26 @@ import (
 	"context"
 	"fmt"
 	"github.com/google/uuid"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var getRelevantDeploymentFunc = getRelevantDeployment
 
-func (r *GopassRepositoryReconciler) createRepositoryServer(ctx context.Context, namespacedName types.NamespacedName) (bool, error) {
+// createRepositoryServer creates the Deployment and Service of the repository server or repairs them if they have been
+// changed. The Service always selects the pods of the existing Deployment.
+func (r *GopassRepositoryReconciler) createRepositoryServer(ctx context.Context, repository *gopassv1alpha1.GopassRepository) (bool, error) {
+	namespacedName := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
 	appName := namespacedName.Name + "-" + uuid.New().String()
 
 	var deployment *appsv1.Deployment
@@ -28,11 +37,27 @@ func (r *GopassRepositoryReconciler) createRepositoryServer(ctx context.Context,
 		r.Log.Info("creating deployment")
 
 		deployment = r.createDeployment(namespacedName, appName)
-		err := r.Client.Create(ctx, deployment)
+		err = r.setOwner(repository, deployment)
+		if err != nil {
+			r.Log.Error(err, "unable to set owner of deployment")
+			return false, err
+		}
+
+		err = r.Client.Create(ctx, deployment)
 		if err != nil {
 			r.Log.Error(err, "unable to create deployment")
 			return false, err
 		}
+	} else {
+		if existingAppName := deployment.Labels["app"]; existingAppName != "" {
+			appName = existingAppName
+		}
+
+		err = r.repairDeployment(ctx, repository, deployment, appName)
+		if err != nil {
+			r.Log.Error(err, "unable to repair deployment")
+			return false, err
+		}
 	}
 
 	var service *corev1.Service
@@ -45,11 +70,23 @@ func (r *GopassRepositoryReconciler) createRepositoryServer(ctx context.Context,
 	if service == nil {
 		r.Log.Info("creating service")
 		service = r.createService(namespacedName, appName)
-		err := r.Client.Create(ctx, service)
+		err = r.setOwner(repository, service)
+		if err != nil {
+			r.Log.Error(err, "unable to set owner of service")
+			return false, err
+		}
+
+		err = r.Client.Create(ctx, service)
 		if err != nil {
 			r.Log.Error(err, "unable to create service")
 			return false, err
 		}
+	} else {
+		err = r.repairService(ctx, repository, service, appName)
+		if err != nil {
+			r.Log.Error(err, "unable to repair service")
+			return false, err
+		}
 	}
 
 	availableReplicas := deployment.Status.AvailableReplicas
@@ -63,6 +100,111 @@ func (r *GopassRepositoryReconciler) createRepositoryServer(ctx context.Context,
 	return false, nil
 }
 
+// setOwner makes the GopassRepository the controller of the object, so it is garbage collected along with it. Owner
+// references cannot cross namespaces, so objects in another namespace are only linked by their labels.
+func (r *GopassRepositoryReconciler) setOwner(repository *gopassv1alpha1.GopassRepository, object metav1.Object) error {
+	if repository.Namespace != object.GetNamespace() {
+		return nil
+	}
+	return controllerutil.SetControllerReference(repository, object, r.Scheme)
+}
+
+// needsOwner reports whether the object lacks the owner reference setOwner would add.
+func needsOwner(repository *gopassv1alpha1.GopassRepository, object metav1.Object) bool {
+	return repository.Namespace == object.GetNamespace() && !metav1.IsControlledBy(object, repository)
+}
+
+// repairDeployment restores the replicas and pod template of the Deployment if they have been changed.
+func (r *GopassRepositoryReconciler) repairDeployment(ctx context.Context, repository *gopassv1alpha1.GopassRepository, deployment *appsv1.Deployment, appName string) error {
+	wanted := r.createDeployment(types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}, appName)
+
+	if equality.Semantic.DeepEqual(deployment.Spec.Replicas, wanted.Spec.Replicas) &&
+		equality.Semantic.DeepDerivative(wanted.Spec.Template, deployment.Spec.Template) &&
+		!needsOwner(repository, deployment) {
+		return nil
+	}
+
+	r.Log.Info("repairing deployment", "deployment", deployment.Name)
+	deployment.Spec.Replicas = wanted.Spec.Replicas
+	deployment.Spec.Template = wanted.Spec.Template
+	err := r.setOwner(repository, deployment)
+	if err != nil {
+		return err
+	}
+	return r.Client.Update(ctx, deployment)
+}
+
+// repairService restores the selector and ports of the Service if they have been changed.
+func (r *GopassRepositoryReconciler) repairService(ctx context.Context, repository *gopassv1alpha1.GopassRepository, service *corev1.Service, appName string) error {
+	wanted := r.createService(types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}, appName)
+
+	if equality.Semantic.DeepEqual(service.Spec.Selector, wanted.Spec.Selector) &&
+		equality.Semantic.DeepDerivative(wanted.Spec.Ports, service.Spec.Ports) &&
+		!needsOwner(repository, service) {
+		return nil
+	}
+
+	r.Log.Info("repairing service", "service", service.Name)
+	service.Spec.Selector = wanted.Spec.Selector
+	service.Spec.Ports = wanted.Spec.Ports
+	err := r.setOwner(repository, service)
+	if err != nil {
+		return err
+	}
+	return r.Client.Update(ctx, service)
+}
+
+// repositoryOfServer enqueues the GopassRepository a Deployment or Service of a repository server belongs to. The
+// labels are used instead of owner references, as the server may run in another namespace than the GopassRepository.
+func repositoryOfServer(object client.Object) []reconcile.Request {
+	name, ok := object.GetLabels()["gopassRepoName"]
+	if !ok {
+		return nil
+	}
+	namespace, ok := object.GetLabels()["gopassRepoNamespace"]
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
+	}
+}
+
+// isRepositoryServer reports whether the object belongs to a repository server run by this controller.
+func (r *GopassRepositoryReconciler) isRepositoryServer(object client.Object) bool {
+	_, hasName := object.GetLabels()["gopassRepoName"]
+	_, hasNamespace := object.GetLabels()["gopassRepoNamespace"]
+	return object.GetNamespace() == r.Namespace && hasName && hasNamespace
+}
+
+// serverPredicate passes the changes of a repository server's Deployment or Service which need to be repaired. Status
+// changes, e.g. during a rollout, are ignored, as well as creations, which are done by the controller itself.
+// specChanged detects the relevant updates of the kind of object.
+func (r *GopassRepositoryReconciler) serverPredicate(specChanged predicate.Predicate) predicate.Predicate {
+	return predicate.And(
+		predicate.NewPredicateFuncs(r.isRepositoryServer),
+		predicate.Funcs{CreateFunc: func(event.CreateEvent) bool { return false }},
+		predicate.Or(specChanged, predicate.LabelChangedPredicate{}),
+	)
+}
+
+// serviceSpecChangedPredicate passes updates changing the spec of a Service. Services do not have a generation, so
+// GenerationChangedPredicate would ignore them.
+var serviceSpecChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldService, ok := e.ObjectOld.(*corev1.Service)
+		if !ok {
+			return false
+		}
+		newService, ok := e.ObjectNew.(*corev1.Service)
+		if !ok {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldService.Spec, newService.Spec)
+	},
+}
+
 func (r *GopassRepositoryReconciler) getDeployment(ctx context.Context, namespacedName types.NamespacedName) (*appsv1.Deployment, error) {
 	labelSelector := labels.Set{
 		"gopassRepoName":      namespacedName.Name,
@@ -4,12 +4,19 @@ import (
 	"context"
 	"github.com/go-logr/logr"
 	logr_testing "github.com/go-logr/logr/testing"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"testing"
 )
 
@@ -61,7 +68,9 @@ func TestGopassRepositoryReconciler_createRepositoryServer(t *testing.T) {
 				Scheme:    tt.fields.Scheme,
 				Namespace: tt.fields.Namespace,
 			}
-			got, err := r.createRepositoryServer(tt.args.ctx, tt.args.namespacedName)
+			got, err := r.createRepositoryServer(tt.args.ctx, &gopassv1alpha1.GopassRepository{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.args.namespacedName.Namespace, Name: tt.args.namespacedName.Name},
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createRepositoryServer() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -126,3 +135,235 @@ func TestGopassRepositoryReconciler_createRepositoryServer(t *testing.T) {
 		})
 	}
 }
+
+func TestGopassRepositoryReconciler_createRepositoryServerSetsOwner(t *testing.T) {
+	tests := []struct {
+		name                string
+		repositoryNamespace string
+		wantedOwner         bool
+	}{
+		{name: "repository in namespace of the server", repositoryNamespace: "test-namespace", wantedOwner: true},
+		{name: "repository in other namespace", repositoryNamespace: "repoNamespace", wantedOwner: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GopassRepositoryReconciler{
+				Client:    fake.NewClientBuilder().Build(),
+				Log:       logr_testing.NullLogger{},
+				Scheme:    scheme.Scheme,
+				Namespace: "test-namespace",
+			}
+			repository := &gopassv1alpha1.GopassRepository{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.repositoryNamespace, Name: "repoName", UID: "repo-uid"},
+			}
+
+			_, err := r.createRepositoryServer(context.Background(), repository)
+			if err != nil {
+				t.Errorf("createRepositoryServer() error = %v", err)
+				return
+			}
+
+			deployment, err := r.getDeployment(context.Background(), types.NamespacedName{Namespace: tt.repositoryNamespace, Name: "repoName"})
+			if err != nil || deployment == nil {
+				t.Errorf("unable to fetch deployment: %v", err)
+				return
+			}
+			service, err := r.getService(context.Background(), types.NamespacedName{Namespace: tt.repositoryNamespace, Name: "repoName"})
+			if err != nil || service == nil {
+				t.Errorf("unable to fetch service: %v", err)
+				return
+			}
+
+			if metav1.IsControlledBy(deployment, repository) != tt.wantedOwner {
+				t.Errorf("deployment has owner references %v, wanted owner %v", deployment.OwnerReferences, tt.wantedOwner)
+			}
+			if metav1.IsControlledBy(service, repository) != tt.wantedOwner {
+				t.Errorf("service has owner references %v, wanted owner %v", service.OwnerReferences, tt.wantedOwner)
+			}
+		})
+	}
+}
+
+func TestGopassRepositoryReconciler_createRepositoryServerRepairsDrift(t *testing.T) {
+	r := &GopassRepositoryReconciler{
+		Client:    fake.NewClientBuilder().Build(),
+		Log:       logr_testing.NullLogger{},
+		Scheme:    scheme.Scheme,
+		Namespace: "test-namespace",
+	}
+	repository := &gopassv1alpha1.GopassRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "repoNamespace", Name: "repoName"},
+	}
+	namespacedName := types.NamespacedName{Namespace: "repoNamespace", Name: "repoName"}
+
+	_, err := r.createRepositoryServer(context.Background(), repository)
+	if err != nil {
+		t.Errorf("createRepositoryServer() error = %v", err)
+		return
+	}
+
+	deployment, err := r.getDeployment(context.Background(), namespacedName)
+	if err != nil || deployment == nil {
+		t.Errorf("unable to fetch deployment: %v", err)
+		return
+	}
+	deployment.Spec.Replicas = getIntPointer(0)
+	deployment.Spec.Template.Spec.Containers[0].Image = "someone-elses-image:latest"
+	err = r.Client.Update(context.Background(), deployment)
+	if err != nil {
+		t.Errorf("unable to update deployment: %v", err)
+		return
+	}
+
+	service, err := r.getService(context.Background(), namespacedName)
+	if err != nil || service == nil {
+		t.Errorf("unable to fetch service: %v", err)
+		return
+	}
+	err = r.deleteService(context.Background(), service)
+	if err != nil {
+		t.Errorf("unable to delete service: %v", err)
+		return
+	}
+
+	_, err = r.createRepositoryServer(context.Background(), repository)
+	if err != nil {
+		t.Errorf("createRepositoryServer() error = %v", err)
+		return
+	}
+
+	deployment, err = r.getDeployment(context.Background(), namespacedName)
+	if err != nil || deployment == nil {
+		t.Errorf("unable to fetch deployment: %v", err)
+		return
+	}
+	if *deployment.Spec.Replicas != 1 {
+		t.Errorf("deployment has %d replicas, wanted 1", *deployment.Spec.Replicas)
+	}
+	if deployment.Spec.Template.Spec.Containers[0].Image != "gopass-server:latest" {
+		t.Errorf("deployment uses image '%s', wanted 'gopass-server:latest'", deployment.Spec.Template.Spec.Containers[0].Image)
+	}
+
+	service, err = r.getService(context.Background(), namespacedName)
+	if err != nil || service == nil {
+		t.Errorf("service has not been recreated: %v", err)
+		return
+	}
+	if service.Spec.Selector["app"] != deployment.Labels["app"] {
+		t.Errorf("service selects app '%s', wanted app '%s' of the deployment", service.Spec.Selector["app"], deployment.Labels["app"])
+	}
+}
+
+func TestRepositoryOfServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		wanted []reconcile.Request
+	}{
+		{
+			name:   "server of a repository",
+			labels: map[string]string{"app": "repoName-1234", "gopassRepoName": "repoName", "gopassRepoNamespace": "repoNamespace"},
+			wanted: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "repoNamespace", Name: "repoName"}}},
+		},
+		{
+			name:   "unrelated object",
+			labels: map[string]string{"app": "something-else"},
+			wanted: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repositoryOfServer(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}})
+			if !reflect.DeepEqual(got, tt.wanted) {
+				t.Errorf("repositoryOfServer() = %v, wanted %v", got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestGopassRepositoryReconciler_serverPredicate(t *testing.T) {
+	r := &GopassRepositoryReconciler{Namespace: "test-namespace"}
+	serverLabels := map[string]string{"app": "repoName-1234", "gopassRepoName": "repoName", "gopassRepoNamespace": "repoNamespace"}
+
+	deployment := func(namespace string, labels map[string]string, generation int64, availableReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "repoName-abcde", Labels: labels, Generation: generation},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: availableReplicas},
+		}
+	}
+	service := func(selector string, loadBalancerIP string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "repoName-abcde", Labels: serverLabels},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": selector}},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: loadBalancerIP}},
+			}},
+		}
+	}
+	deploymentPredicate := r.serverPredicate(predicate.GenerationChangedPredicate{})
+	servicePredicate := r.serverPredicate(serviceSpecChangedPredicate)
+
+	tests := []struct {
+		name   string
+		got    bool
+		wanted bool
+	}{
+		{
+			name:   "status of deployment changed",
+			got:    deploymentPredicate.Update(event.UpdateEvent{ObjectOld: deployment("test-namespace", serverLabels, 1, 0), ObjectNew: deployment("test-namespace", serverLabels, 1, 1)}),
+			wanted: false,
+		},
+		{
+			name:   "spec of deployment changed",
+			got:    deploymentPredicate.Update(event.UpdateEvent{ObjectOld: deployment("test-namespace", serverLabels, 1, 1), ObjectNew: deployment("test-namespace", serverLabels, 2, 1)}),
+			wanted: true,
+		},
+		{
+			name:   "labels of deployment changed",
+			got:    deploymentPredicate.Update(event.UpdateEvent{ObjectOld: deployment("test-namespace", serverLabels, 1, 1), ObjectNew: deployment("test-namespace", map[string]string{"gopassRepoName": "repoName", "gopassRepoNamespace": "repoNamespace"}, 1, 1)}),
+			wanted: true,
+		},
+		{
+			name:   "deployment deleted",
+			got:    deploymentPredicate.Delete(event.DeleteEvent{Object: deployment("test-namespace", serverLabels, 1, 1)}),
+			wanted: true,
+		},
+		{
+			name:   "deployment created",
+			got:    deploymentPredicate.Create(event.CreateEvent{Object: deployment("test-namespace", serverLabels, 1, 0)}),
+			wanted: false,
+		},
+		{
+			name:   "deployment in other namespace deleted",
+			got:    deploymentPredicate.Delete(event.DeleteEvent{Object: deployment("other-namespace", serverLabels, 1, 1)}),
+			wanted: false,
+		},
+		{
+			name:   "unrelated deployment deleted",
+			got:    deploymentPredicate.Delete(event.DeleteEvent{Object: deployment("test-namespace", map[string]string{"app": "something-else"}, 1, 1)}),
+			wanted: false,
+		},
+		{
+			name:   "selector of service changed",
+			got:    servicePredicate.Update(event.UpdateEvent{ObjectOld: service("repoName-1234", ""), ObjectNew: service("something-else", "")}),
+			wanted: true,
+		},
+		{
+			name:   "status of service changed",
+			got:    servicePredicate.Update(event.UpdateEvent{ObjectOld: service("repoName-1234", ""), ObjectNew: service("repoName-1234", "10.0.0.1")}),
+			wanted: false,
+		},
+		{
+			name:   "service deleted",
+			got:    servicePredicate.Delete(event.DeleteEvent{Object: service("repoName-1234", "")}),
+			wanted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.wanted {
+				t.Errorf("predicate returned %v, wanted %v", tt.got, tt.wanted)
+			}
+		})
+	}
+}
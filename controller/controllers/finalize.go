@@ -23,7 +23,7 @@ func (r *GopassRepositoryReconciler) handleDeletionOfResource(ctx context.Contex
 		}
 	} else {
 		if containsString(repository.ObjectMeta.Finalizers, finalizerName) {
-			err := r.deleteExternalResources(ctx, req.NamespacedName, serviceClient)
+			err := r.deleteExternalResources(ctx, req.NamespacedName, repository.Spec, serviceClient)
 			if err != nil {
 				return ctrl.Result{}, err, true
 			}
@@ -38,14 +38,11 @@ func (r *GopassRepositoryReconciler) handleDeletionOfResource(ctx context.Contex
 	return ctrl.Result{}, nil, false
 }
 
-func (r *GopassRepositoryReconciler) deleteExternalResources(ctx context.Context, namespacedName types.NamespacedName, serviceClient gopass_repository.RepositoryServiceClient) error {
-	if serviceClient != nil {
-		secret, err := serviceClient.DeleteSecret(ctx, &gopass_repository.Repository{
-			SecretName: &gopass_repository.NamespacedName{
-				Namespace: namespacedName.Namespace,
-				Name:      namespacedName.Name,
-			},
-		})
+func (r *GopassRepositoryReconciler) deleteExternalResources(ctx context.Context, namespacedName types.NamespacedName, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec, serviceClient gopass_repository.RepositoryServiceClient) error {
+	if gopassRepositorySpec.DeletionPolicy == gopassv1alpha1.DeletionPolicyOrphan {
+		r.Log.Info("deletion policy is Orphan, keeping secrets")
+	} else if serviceClient != nil {
+		secret, err := serviceClient.DeleteSecret(ctx, createRepository(namespacedName, gopassRepositorySpec))
 		if err != nil {
 			r.Log.Error(err, "unable to delete secret")
 			return err
@@ -57,6 +54,14 @@ func (r *GopassRepositoryReconciler) deleteExternalResources(ctx context.Context
 		}
 	}
 
+	if serviceClient != nil {
+		// the clone and key material are removed with the server as well, so a failure does not block the deletion
+		err := removeRepository(ctx, serviceClient, gopassRepositorySpec.RepositoryURL)
+		if err != nil {
+			r.Log.Error(err, "unable to remove repository from repository server")
+		}
+	}
+
 	deployment, err := r.getDeployment(ctx, namespacedName)
 	if err != nil {
 		r.Log.Error(err, "unable to get deployment for deleteExternalResources")
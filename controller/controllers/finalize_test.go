@@ -31,6 +31,7 @@ func NewTestRepositoryServiceClient() *TestRepositoryServiceClient {
 			"UpdateRepository":     {},
 			"UpdateAllPasswords":   {},
 			"DeleteSecret":         {},
+			"RemoveRepository":     {},
 		},
 	}
 }
@@ -56,7 +57,16 @@ func (r *TestRepositoryServiceClient) UpdateAllPasswords(_ context.Context, _ *g
 	}, nil
 }
 
-func (r *TestRepositoryServiceClient) DeleteSecret(_ context.Context, _ *gopass_repository.Repository, _ ...grpc.CallOption) (*gopass_repository.RepositoryResponse, error) {
+func (r *TestRepositoryServiceClient) DeleteSecret(_ context.Context, repository *gopass_repository.Repository, _ ...grpc.CallOption) (*gopass_repository.RepositoryResponse, error) {
+	r.Calls["DeleteSecret"] = append(r.Calls["DeleteSecret"], repository.SecretName.GetName())
+	return &gopass_repository.RepositoryResponse{
+		Successful:   true,
+		ErrorMessage: "",
+	}, nil
+}
+
+func (r *TestRepositoryServiceClient) RemoveRepository(_ context.Context, repository *gopass_repository.Repository, _ ...grpc.CallOption) (*gopass_repository.RepositoryResponse, error) {
+	r.Calls["RemoveRepository"] = append(r.Calls["RemoveRepository"], repository.RepositoryURL)
 	return &gopass_repository.RepositoryResponse{
 		Successful:   true,
 		ErrorMessage: "",
@@ -296,3 +306,42 @@ func TestGopassRepositoryReconciler_handleDeletionOfResource(t *testing.T) {
 		})
 	}
 }
+
+func TestGopassRepositoryReconciler_deleteExternalResources(t *testing.T) {
+	tests := []struct {
+		name                string
+		deletionPolicy      string
+		wantedDeleteSecrets int
+	}{
+		{name: "secrets are deleted by default", deletionPolicy: "", wantedDeleteSecrets: 1},
+		{name: "secrets are deleted with policy Delete", deletionPolicy: gopassv1alpha1.DeletionPolicyDelete, wantedDeleteSecrets: 1},
+		{name: "secrets are kept with policy Orphan", deletionPolicy: gopassv1alpha1.DeletionPolicyOrphan, wantedDeleteSecrets: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GopassRepositoryReconciler{
+				Client: fake.NewClientBuilder().Build(),
+				Log:    logr_testing.NullLogger{},
+				Scheme: scheme.Scheme,
+			}
+			serviceClient := NewTestRepositoryServiceClient()
+
+			err := r.deleteExternalResources(context.Background(), types.NamespacedName{Namespace: "test-namespace", Name: "test-repository"}, gopassv1alpha1.GopassRepositorySpec{
+				RepositoryURL:  "https://example.com/password-store.git",
+				DeletionPolicy: tt.deletionPolicy,
+			}, serviceClient)
+			if err != nil {
+				t.Errorf("deleteExternalResources() error = %v", err)
+			}
+
+			if len(serviceClient.Calls["DeleteSecret"]) != tt.wantedDeleteSecrets {
+				t.Errorf("DeleteSecret was called %d times, wanted %d", len(serviceClient.Calls["DeleteSecret"]), tt.wantedDeleteSecrets)
+			}
+
+			wantedRemovals := []string{"https://example.com/password-store.git"}
+			if !reflect.DeepEqual(serviceClient.Calls["RemoveRepository"], wantedRemovals) {
+				t.Errorf("RemoveRepository was called with %v, wanted %v", serviceClient.Calls["RemoveRepository"], wantedRemovals)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ func InitializeTestRepositoryServer() *TestRepositoryServer {
 			"UpdateRepository":     {},
 			"UpdateAllPasswords":   {},
 			"DeleteSecret":         {},
+			"RemoveRepository":     {},
 		},
 	}
 }
@@ -51,3 +52,11 @@ func (r *TestRepositoryServer) DeleteSecret(_ context.Context, repository *gopas
 		ErrorMessage: "",
 	}, nil
 }
+
+func (r *TestRepositoryServer) RemoveRepository(_ context.Context, repository *gopass_repository.Repository) (*gopass_repository.RepositoryResponse, error) {
+	r.Calls["RemoveRepository"] = append(r.Calls["RemoveRepository"], repository.RepositoryURL)
+	return &gopass_repository.RepositoryResponse{
+		Successful:   true,
+		ErrorMessage: "",
+	}, nil
+}
@@ -24,9 +24,16 @@ import (
 	"github.com/go-logr/logr"
 	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var createRepositoryServiceClientFunc = createRepositoryServiceClient
@@ -44,6 +51,8 @@ type GopassRepositoryReconciler struct {
 // +kubebuilder:rbac:groups=gopass.gopass.operator,resources=gopassrepositories/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -85,43 +94,63 @@ func (r *GopassRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return result, err
 	}
 
-	deploymentFinished, err := r.createRepositoryServer(ctx, req.NamespacedName)
+	base := gopassRepository.DeepCopy()
+	defer r.updateStatus(ctx, gopassRepository, base)
+
+	setHostKeyCondition(gopassRepository)
+
+	deploymentFinished, err := r.createRepositoryServer(ctx, gopassRepository)
 	if err != nil {
 		log.Error(err, "not able to deploy repository server")
+		setFailedCondition(gopassRepository, gopassv1alpha1.ConditionServerAvailable, reasonDeploymentFailed, err)
 		return ctrl.Result{}, err
 	}
 
 	if !deploymentFinished {
 		log.Info("deployment not yet ready, trying again later")
+		setCondition(gopassRepository, gopassv1alpha1.ConditionServerAvailable, metav1.ConditionFalse, reasonDeploymentInProgress, "deployment of repository server in progress")
+		setCondition(gopassRepository, gopassv1alpha1.ConditionReady, metav1.ConditionFalse, reasonDeploymentInProgress, "deployment of repository server in progress")
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
 	if service == nil {
 		log.Info("service did not exist yet, trying again later")
+		setCondition(gopassRepository, gopassv1alpha1.ConditionServerAvailable, metav1.ConditionFalse, reasonServiceNotReady, "service of repository server does not exist yet")
+		setCondition(gopassRepository, gopassv1alpha1.ConditionReady, metav1.ConditionFalse, reasonServiceNotReady, "service of repository server does not exist yet")
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
+	setCondition(gopassRepository, gopassv1alpha1.ConditionServerAvailable, metav1.ConditionTrue, reasonServerReady, "repository server is available")
 
-	err = initializeRepository(ctx, log, gopassRepository.Spec.RepositoryURL, repositoryServiceClient, req.Namespace, gopassRepository.Spec)
+	removePreviousRepository(ctx, log, repositoryServiceClient, gopassRepository)
+
+	_, err = initializeRepository(ctx, log, gopassRepository.Spec.RepositoryURL, repositoryServiceClient, req.Namespace, gopassRepository.Spec)
 	if err != nil {
 		log.Error(err, "unable to initialize repository")
+		setFailedCondition(gopassRepository, gopassv1alpha1.ConditionRepositoryInitialized, reasonInitializationFailed, err)
 		return ctrl.Result{}, err
 	}
+	gopassRepository.Status.RepositoryURL = gopassRepository.Spec.RepositoryURL
+	setCondition(gopassRepository, gopassv1alpha1.ConditionRepositoryInitialized, metav1.ConditionTrue, reasonInitialized, "repository has been initialized")
 
-	err = updateRepository(ctx, req, repositoryServiceClient, gopassRepository)
+	_, err = updateRepository(ctx, req, repositoryServiceClient, gopassRepository)
 	if err != nil {
 		log.Error(err, "unable to update repository")
+		setFailedCondition(gopassRepository, gopassv1alpha1.ConditionSynced, reasonUpdateFailed, err)
 		return ctrl.Result{}, err
 	}
 
-	err = updateAllPasswords(ctx, log, req.NamespacedName, gopassRepository.Spec.RepositoryURL, repositoryServiceClient)
+	syncResponse, err := updateAllPasswords(ctx, log, req.NamespacedName, gopassRepository.Spec, repositoryServiceClient)
 	if err != nil {
 		log.Error(err, "unable to fetch secrets")
+		setSyncFailedStatus(gopassRepository, err)
 		return ctrl.Result{}, err
 	}
+	setSyncedStatus(gopassRepository, syncResponse)
 
 	interval, err := parseRefreshInterval(gopassRepository.Spec.RefreshInterval)
 	if err != nil {
 		log.Error(err, "unable to parse refresh interval")
+		setFailedCondition(gopassRepository, gopassv1alpha1.ConditionReady, reasonInvalidSpec, err)
 		return ctrl.Result{}, err
 	}
 
@@ -149,8 +178,43 @@ func parseRefreshInterval(refreshInterval string) (time.Duration, error) {
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Updates that do not change the generation, e.g. status updates, are ignored to not trigger a sync on every status write.
+// Changes of referenced Secrets and ConfigMaps trigger a reconciliation of the repositories referencing them. Only their
+// metadata is cached. Changes of the spec or labels of the Deployment and Service of a repository server as well as their
+// deletion trigger a reconciliation of their repository, so they are repaired right away.
 func (r *GopassRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &gopassv1alpha1.GopassRepository{}, referencedSecretsField, referencedSecrets)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &gopassv1alpha1.GopassRepository{}, referencedConfigMapsField, referencedConfigMaps)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&gopassv1alpha1.GopassRepository{}).
+		For(&gopassv1alpha1.GopassRepository{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.repositoriesReferencingSecret),
+			builder.OnlyMetadata,
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.repositoriesReferencingConfigMap),
+			builder.OnlyMetadata,
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &appsv1.Deployment{}},
+			handler.EnqueueRequestsFromMapFunc(repositoryOfServer),
+			builder.WithPredicates(r.serverPredicate(predicate.GenerationChangedPredicate{})),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Service{}},
+			handler.EnqueueRequestsFromMapFunc(repositoryOfServer),
+			builder.WithPredicates(r.serverPredicate(serviceSpecChangedPredicate)),
+		).
 		Complete(r)
 }
@@ -129,9 +129,11 @@ func createRepositoryServiceClientForTesting(targetUrl string) (gopass_repositor
 }
 
 func getRelevantDeploymentMock(deployments *[]appsv1.Deployment) (*appsv1.Deployment, error) {
-	return &appsv1.Deployment{
-		Status: appsv1.DeploymentStatus{
-			AvailableReplicas: 1,
-		},
-	}, nil
+	if len(*deployments) == 0 {
+		return nil, nil
+	}
+
+	deployment := (*deployments)[0].DeepCopy()
+	deployment.Status.AvailableReplicas = 1
+	return deployment, nil
 }
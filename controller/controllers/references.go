@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// referencedSecretsField indexes GopassRepositories by the names of the Secrets they reference.
+	referencedSecretsField = ".spec.referencedSecrets"
+	// referencedConfigMapsField indexes GopassRepositories by the names of the ConfigMaps they reference.
+	referencedConfigMapsField = ".spec.referencedConfigMaps"
+)
+
+// referencedSecrets returns the names of the Secrets in the namespace of the GopassRepository it reads credentials,
+// CA bundles, known hosts and GPG keys from.
+func referencedSecrets(object client.Object) []string {
+	return referencedResources(object, "Secret")
+}
+
+// referencedConfigMaps returns the names of the ConfigMaps in the namespace of the GopassRepository it reads CA bundles
+// and known hosts from.
+func referencedConfigMaps(object client.Object) []string {
+	return referencedResources(object, "ConfigMap")
+}
+
+// referencedResources returns the names of the resources of the given kind referenced by the GopassRepository.
+// References without a kind point to Secrets.
+func referencedResources(object client.Object, kind string) []string {
+	repository, ok := object.(*gopassv1alpha1.GopassRepository)
+	if !ok {
+		return nil
+	}
+	spec := repository.Spec
+
+	names := make([]string, 0)
+	add := func(referenceKind string, name string) {
+		if referenceKind == "" {
+			referenceKind = "Secret"
+		}
+		if referenceKind == kind && name != "" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	addResource := func(reference *gopassv1alpha1.ResourceKeyRefSpec) {
+		if reference != nil {
+			add(reference.Kind, reference.Name)
+		}
+	}
+
+	add("Secret", spec.SecretKeyRef.Name)
+	if spec.SSHKeyRef != nil {
+		add("Secret", spec.SSHKeyRef.Name)
+	}
+	addResource(spec.CABundleRef)
+	addResource(spec.KnownHostsRef)
+	add("Secret", spec.GpgKeyRef.Name)
+	if spec.GpgPassphraseRef != nil {
+		add("Secret", spec.GpgPassphraseRef.Name)
+	}
+	for _, mount := range spec.Mounts {
+		if mount.SecretKeyRef != nil {
+			add("Secret", mount.SecretKeyRef.Name)
+		}
+		if mount.SSHKeyRef != nil {
+			add("Secret", mount.SSHKeyRef.Name)
+		}
+	}
+
+	return names
+}
+
+// repositoriesReferencingSecret enqueues all GopassRepositories referencing the Secret, so changed credentials and GPG
+// keys are picked up immediately.
+func (r *GopassRepositoryReconciler) repositoriesReferencingSecret(secret client.Object) []reconcile.Request {
+	return r.repositoriesReferencing(secret, referencedSecretsField, referencedSecrets)
+}
+
+// repositoriesReferencingConfigMap enqueues all GopassRepositories referencing the ConfigMap, so changed CA bundles and
+// known hosts are picked up immediately.
+func (r *GopassRepositoryReconciler) repositoriesReferencingConfigMap(configMap client.Object) []reconcile.Request {
+	return r.repositoriesReferencing(configMap, referencedConfigMapsField, referencedConfigMaps)
+}
+
+// repositoriesReferencing enqueues all GopassRepositories in the namespace of the object whose references, indexed by
+// field, contain its name.
+func (r *GopassRepositoryReconciler) repositoriesReferencing(object client.Object, field string, references func(client.Object) []string) []reconcile.Request {
+	repositories := &gopassv1alpha1.GopassRepositoryList{}
+	err := r.List(context.Background(), repositories, client.InNamespace(object.GetNamespace()), client.MatchingFields{field: object.GetName()})
+	if err != nil {
+		r.Log.Error(err, "unable to list repositories referencing resource", "resource", object.GetName(), "namespace", object.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, repository := range repositories.Items {
+		if !containsString(references(&repository), object.GetName()) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name},
+		})
+	}
+	return requests
+}
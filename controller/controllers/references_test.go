@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	logr_testing "github.com/go-logr/logr/testing"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+func TestReferencedSecrets(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{
+		Spec: gopassv1alpha1.GopassRepositorySpec{
+			SecretKeyRef:     gopassv1alpha1.SecretKeyRefSpec{Name: "git-credentials", Key: "password"},
+			SSHKeyRef:        &gopassv1alpha1.SSHKeyRefSpec{Name: "deploy-key", Key: "id_ed25519"},
+			CABundleRef:      &gopassv1alpha1.ResourceKeyRefSpec{Kind: "ConfigMap", Name: "internal-ca"},
+			KnownHostsRef:    &gopassv1alpha1.ResourceKeyRefSpec{Name: "known-hosts"},
+			GpgKeyRef:        gopassv1alpha1.SecretKeyRefSpec{Name: "gpg-key", Key: "private.key"},
+			GpgPassphraseRef: &gopassv1alpha1.SecretKeyRefSpec{Name: "gpg-key", Key: "passphrase"},
+			Mounts: []gopassv1alpha1.MountSpec{
+				{MountPoint: "shared", SecretKeyRef: &gopassv1alpha1.SecretKeyRefSpec{Name: "shared-credentials", Key: "token"}},
+			},
+		},
+	}
+
+	wanted := []string{"git-credentials", "deploy-key", "known-hosts", "gpg-key", "shared-credentials"}
+	got := referencedSecrets(repository)
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("referencedSecrets() = %v, wanted %v", got, wanted)
+	}
+}
+
+func TestRepositoriesReferencingSecret(t *testing.T) {
+	repository := func(namespace string, name string, gpgKey string) *gopassv1alpha1.GopassRepository {
+		return &gopassv1alpha1.GopassRepository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: gopassv1alpha1.GopassRepositorySpec{
+				GpgKeyRef: gopassv1alpha1.SecretKeyRefSpec{Name: gpgKey, Key: "private.key"},
+			},
+		}
+	}
+
+	r := &GopassRepositoryReconciler{
+		Client: fake.NewClientBuilder().WithRuntimeObjects(
+			repository("team-a", "first", "gpg-key"),
+			repository("team-a", "second", "gpg-key"),
+			repository("team-a", "other-key", "other-gpg-key"),
+			repository("team-b", "other-namespace", "gpg-key"),
+		).Build(),
+		Log:    logr_testing.NullLogger{},
+		Scheme: scheme.Scheme,
+	}
+
+	got := r.repositoriesReferencingSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "gpg-key"}})
+
+	wanted := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "first"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "second"}},
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("repositoriesReferencingSecret() = %v, wanted %v", got, wanted)
+	}
+}
+
+func TestReferencedConfigMaps(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{
+		Spec: gopassv1alpha1.GopassRepositorySpec{
+			SecretKeyRef:  gopassv1alpha1.SecretKeyRefSpec{Name: "git-credentials", Key: "password"},
+			CABundleRef:   &gopassv1alpha1.ResourceKeyRefSpec{Kind: "ConfigMap", Name: "internal-ca"},
+			KnownHostsRef: &gopassv1alpha1.ResourceKeyRefSpec{Kind: "ConfigMap", Name: "known-hosts"},
+			GpgKeyRef:     gopassv1alpha1.SecretKeyRefSpec{Name: "gpg-key", Key: "private.key"},
+		},
+	}
+
+	wanted := []string{"internal-ca", "known-hosts"}
+	got := referencedConfigMaps(repository)
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("referencedConfigMaps() = %v, wanted %v", got, wanted)
+	}
+}
+
+func TestRepositoriesReferencingConfigMap(t *testing.T) {
+	repository := func(namespace string, name string, kind string) *gopassv1alpha1.GopassRepository {
+		return &gopassv1alpha1.GopassRepository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: gopassv1alpha1.GopassRepositorySpec{
+				CABundleRef: &gopassv1alpha1.ResourceKeyRefSpec{Kind: kind, Name: "internal-ca", Key: "ca.crt"},
+			},
+		}
+	}
+
+	r := &GopassRepositoryReconciler{
+		Client: fake.NewClientBuilder().WithRuntimeObjects(
+			repository("team-a", "config-map", "ConfigMap"),
+			repository("team-a", "secret", "Secret"),
+			repository("team-b", "other-namespace", "ConfigMap"),
+		).Build(),
+		Log:    logr_testing.NullLogger{},
+		Scheme: scheme.Scheme,
+	}
+
+	got := r.repositoriesReferencingConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "internal-ca"}})
+
+	wanted := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "config-map"}},
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("repositoriesReferencingConfigMap() = %v, wanted %v", got, wanted)
+	}
+}
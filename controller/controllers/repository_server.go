@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-logr/logr"
 	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
@@ -20,30 +21,25 @@ func createRepositoryServiceClient(targetUrl string) (gopass_repository.Reposito
 }
 
 func initializeRepository(ctx context.Context, log logr.Logger, url string, repositoryServiceClient gopass_repository.RepositoryServiceClient,
-	namespace string, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) error {
+	namespace string, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) (*gopass_repository.RepositoryResponse, error) {
 	log.Info("attempting to call repository server")
 	repository, err := repositoryServiceClient.InitializeRepository(
 		ctx,
 		&gopass_repository.RepositoryInitialization{
 			Repository: &gopass_repository.Repository{
-				RepositoryURL: url,
-				Authentication: &gopass_repository.Authentication{
-					Namespace: namespace,
-					Username:  gopassRepositorySpec.UserName,
-					SecretRef: gopassRepositorySpec.SecretKeyRef.Name,
-					SecretKey: gopassRepositorySpec.SecretKeyRef.Key,
-				},
-			},
-			GpgKeyReference: &gopass_repository.GpgKeyReference{
-				GpgKeyRef:    gopassRepositorySpec.GpgKeyRef.Name,
-				GpgKeyRefKey: gopassRepositorySpec.GpgKeyRef.Key,
+				RepositoryURL:  url,
+				Ref:            gopassRepositorySpec.Ref,
+				StorePath:      gopassRepositorySpec.StorePath,
+				Authentication: createAuthentication(namespace, gopassRepositorySpec),
+				Mounts:         createMounts(namespace, gopassRepositorySpec),
 			},
+			GpgKeyReference: createGpgKeyReference(gopassRepositorySpec),
 		},
 	)
 
 	if err != nil {
 		log.Error(err, "invalid response")
-		return err
+		return nil, err
 	}
 
 	if repository != nil {
@@ -52,35 +48,180 @@ func initializeRepository(ctx context.Context, log logr.Logger, url string, repo
 		log.Info("empty response from repository server")
 	}
 
-	return nil
+	return repository, nil
 }
 
-func updateRepository(ctx context.Context, req ctrl.Request, repositoryServiceClient gopass_repository.RepositoryServiceClient, gopassRepository *gopassv1alpha1.GopassRepository) error {
-	_, err := repositoryServiceClient.UpdateRepository(ctx, &gopass_repository.Repository{
-		RepositoryURL: gopassRepository.Spec.RepositoryURL,
-		Authentication: &gopass_repository.Authentication{
-			Namespace: req.NamespacedName.Namespace,
-			Username:  gopassRepository.Spec.UserName,
-			SecretRef: gopassRepository.Spec.SecretKeyRef.Name,
-			SecretKey: gopassRepository.Spec.SecretKeyRef.Key,
-		},
+func updateRepository(ctx context.Context, req ctrl.Request, repositoryServiceClient gopass_repository.RepositoryServiceClient, gopassRepository *gopassv1alpha1.GopassRepository) (*gopass_repository.RepositoryResponse, error) {
+	return repositoryServiceClient.UpdateRepository(ctx, &gopass_repository.Repository{
+		RepositoryURL:  gopassRepository.Spec.RepositoryURL,
+		Ref:            gopassRepository.Spec.Ref,
+		Authentication: createAuthentication(req.NamespacedName.Namespace, gopassRepository.Spec),
+		Mounts:         createMounts(req.NamespacedName.Namespace, gopassRepository.Spec),
 	})
-	return err
 }
 
-func updateAllPasswords(ctx context.Context, log logr.Logger, namespacedName types.NamespacedName, url string, repositoryServiceClient gopass_repository.RepositoryServiceClient) error {
-	_, err := repositoryServiceClient.UpdateAllPasswords(ctx,
-		&gopass_repository.Repository{
-			RepositoryURL: url,
-			SecretName: &gopass_repository.NamespacedName{
-				Namespace: namespacedName.Namespace,
-				Name:      namespacedName.Name,
-			},
+// removeRepository removes the repository with the given URL along with its clone and key material from the repository server.
+func removeRepository(ctx context.Context, repositoryServiceClient gopass_repository.RepositoryServiceClient, url string) error {
+	response, err := repositoryServiceClient.RemoveRepository(ctx, &gopass_repository.Repository{RepositoryURL: url})
+	if err != nil {
+		return err
+	}
+	if !response.Successful {
+		return fmt.Errorf("removal of repository '%s' not successful: %s", url, response.ErrorMessage)
+	}
+	return nil
+}
+
+// removePreviousRepository removes the repository initialized before the URL of the GopassRepository changed, so its
+// clone is not left behind on the repository server.
+func removePreviousRepository(ctx context.Context, log logr.Logger, repositoryServiceClient gopass_repository.RepositoryServiceClient, gopassRepository *gopassv1alpha1.GopassRepository) {
+	previousURL := gopassRepository.Status.RepositoryURL
+	if previousURL == "" || previousURL == gopassRepository.Spec.RepositoryURL {
+		return
+	}
+
+	log.Info("repository URL changed, removing previous repository", "previousRepositoryUrl", previousURL)
+	err := removeRepository(ctx, repositoryServiceClient, previousURL)
+	if err != nil {
+		log.Error(err, "unable to remove previous repository")
+	}
+}
+
+func createAuthentication(namespace string, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) *gopass_repository.Authentication {
+	authentication := &gopass_repository.Authentication{
+		Namespace:             namespace,
+		Username:              gopassRepositorySpec.UserName,
+		SecretRef:             gopassRepositorySpec.SecretKeyRef.Name,
+		SecretKey:             gopassRepositorySpec.SecretKeyRef.Key,
+		InsecureIgnoreHostKey: gopassRepositorySpec.InsecureIgnoreHostKey,
+	}
+
+	if gopassRepositorySpec.SSHKeyRef != nil {
+		authentication.SshKeyRef = gopassRepositorySpec.SSHKeyRef.Name
+		authentication.SshKeyRefKey = gopassRepositorySpec.SSHKeyRef.Key
+		authentication.SshKeyPassphraseKey = gopassRepositorySpec.SSHKeyRef.PassphraseKey
+	}
+
+	if gopassRepositorySpec.CABundleRef != nil {
+		authentication.CaBundleRef = &gopass_repository.ResourceKeyReference{
+			Kind: gopassRepositorySpec.CABundleRef.Kind,
+			Name: gopassRepositorySpec.CABundleRef.Name,
+			Key:  gopassRepositorySpec.CABundleRef.Key,
+		}
+	}
+
+	if gopassRepositorySpec.KnownHostsRef != nil {
+		authentication.KnownHostsRef = &gopass_repository.ResourceKeyReference{
+			Kind: gopassRepositorySpec.KnownHostsRef.Kind,
+			Name: gopassRepositorySpec.KnownHostsRef.Name,
+			Key:  gopassRepositorySpec.KnownHostsRef.Key,
+		}
+	}
+
+	return authentication
+}
+
+// createMounts describes the repositories mounted into the store. Mounts without credentials of their own use the
+// credentials of the repository.
+func createMounts(namespace string, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) []*gopass_repository.Mount {
+	mounts := make([]*gopass_repository.Mount, 0, len(gopassRepositorySpec.Mounts))
+	for _, mount := range gopassRepositorySpec.Mounts {
+		mountSpec := gopassRepositorySpec
+		if mount.UserName != "" || mount.SecretKeyRef != nil || mount.SSHKeyRef != nil {
+			mountSpec.UserName = mount.UserName
+			mountSpec.SecretKeyRef = gopassv1alpha1.SecretKeyRefSpec{}
+			if mount.SecretKeyRef != nil {
+				mountSpec.SecretKeyRef = *mount.SecretKeyRef
+			}
+			mountSpec.SSHKeyRef = mount.SSHKeyRef
+		}
+
+		mounts = append(mounts, &gopass_repository.Mount{
+			MountPoint:     mount.MountPoint,
+			RepositoryURL:  mount.RepositoryURL,
+			Authentication: createAuthentication(namespace, mountSpec),
+			Ref:            mount.Ref,
 		})
+	}
+	return mounts
+}
+
+func createGpgKeyReference(gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) *gopass_repository.GpgKeyReference {
+	gpgKeyReference := &gopass_repository.GpgKeyReference{
+		GpgKeyRef:    gopassRepositorySpec.GpgKeyRef.Name,
+		GpgKeyRefKey: gopassRepositorySpec.GpgKeyRef.Key,
+	}
+
+	if gopassRepositorySpec.GpgPassphraseRef != nil {
+		gpgKeyReference.GpgPassphraseRef = gopassRepositorySpec.GpgPassphraseRef.Name
+		gpgKeyReference.GpgPassphraseRefKey = gopassRepositorySpec.GpgPassphraseRef.Key
+	}
+
+	return gpgKeyReference
+}
+
+func updateAllPasswords(ctx context.Context, log logr.Logger, namespacedName types.NamespacedName, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec, repositoryServiceClient gopass_repository.RepositoryServiceClient) (*gopass_repository.RepositoryResponse, error) {
+	response, err := repositoryServiceClient.UpdateAllPasswords(ctx, createRepository(namespacedName, gopassRepositorySpec))
 
 	if err != nil {
 		log.Error(err, "not able to fetch passwords")
-		return err
+		return nil, err
 	}
-	return nil
+	return response, nil
+}
+
+// createRepository describes which entries of the repository are written to which Secrets.
+func createRepository(namespacedName types.NamespacedName, gopassRepositorySpec gopassv1alpha1.GopassRepositorySpec) *gopass_repository.Repository {
+	repository := &gopass_repository.Repository{
+		RepositoryURL: gopassRepositorySpec.RepositoryURL,
+		SecretName: &gopass_repository.NamespacedName{
+			Namespace: namespacedName.Namespace,
+			Name:      namespacedName.Name,
+		},
+		Include:         gopassRepositorySpec.Include,
+		Exclude:         gopassRepositorySpec.Exclude,
+		KeyNaming:       gopassRepositorySpec.KeyNaming,
+		KeyNamingPrefix: gopassRepositorySpec.KeyNamingPrefix,
+		CollisionPolicy: gopassRepositorySpec.CollisionPolicy,
+		IncludeFields:   gopassRepositorySpec.IncludeFields,
+		Templates:       gopassRepositorySpec.Templates,
+		PrunePolicy:     gopassRepositorySpec.PrunePolicy,
+		DeletionPolicy:  gopassRepositorySpec.DeletionPolicy,
+		Labels:          gopassRepositorySpec.SecretLabels,
+		Annotations:     gopassRepositorySpec.SecretAnnotations,
+	}
+
+	for _, secret := range gopassRepositorySpec.Secrets {
+		target := &gopass_repository.SecretTarget{
+			Name: &gopass_repository.NamespacedName{
+				Namespace: namespacedName.Namespace,
+				Name:      secret.Name,
+			},
+			Prefix:        secret.Prefix,
+			Include:       secret.Include,
+			IncludeFields: secret.IncludeFields,
+			Templates:     secret.Templates,
+			Type:          secret.Type,
+			Kind:          secret.Kind,
+			Labels:        secret.Labels,
+			Annotations:   secret.Annotations,
+		}
+		for _, mapping := range secret.Data {
+			target.Data = append(target.Data, &gopass_repository.KeyMapping{
+				Key:   mapping.Key,
+				Path:  mapping.Path,
+				Field: mapping.Field,
+			})
+		}
+		for _, registry := range secret.Registries {
+			target.Registries = append(target.Registries, &gopass_repository.RegistryCredentials{
+				Server:        registry.Server,
+				Path:          registry.Path,
+				UsernameField: registry.UsernameField,
+			})
+		}
+		repository.Secrets = append(repository.Secrets, target)
+	}
+
+	return repository
 }
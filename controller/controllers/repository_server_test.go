@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"context"
+	logr_testing "github.com/go-logr/logr/testing"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func TestCreateAuthentication(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   gopassv1alpha1.GopassRepositorySpec
+		wanted *gopass_repository.Authentication
+	}{
+		{
+			name: "username and password",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				UserName: "Henry.Dorsett.Case",
+				SecretKeyRef: gopassv1alpha1.SecretKeyRefSpec{
+					Name: "git-credentials",
+					Key:  "password",
+				},
+			},
+			wanted: &gopass_repository.Authentication{
+				Namespace: "test-namespace",
+				Username:  "Henry.Dorsett.Case",
+				SecretRef: "git-credentials",
+				SecretKey: "password",
+			},
+		},
+		{
+			name: "ssh private key with passphrase",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				UserName: "git",
+				SSHKeyRef: &gopassv1alpha1.SSHKeyRefSpec{
+					Name:          "deploy-key",
+					Key:           "id_ed25519",
+					PassphraseKey: "passphrase",
+				},
+			},
+			wanted: &gopass_repository.Authentication{
+				Namespace:           "test-namespace",
+				Username:            "git",
+				SshKeyRef:           "deploy-key",
+				SshKeyRefKey:        "id_ed25519",
+				SshKeyPassphraseKey: "passphrase",
+			},
+		},
+		{
+			name: "token with CA bundle from ConfigMap",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				SecretKeyRef: gopassv1alpha1.SecretKeyRefSpec{
+					Name: "git-credentials",
+					Key:  "token",
+				},
+				CABundleRef: &gopassv1alpha1.ResourceKeyRefSpec{
+					Kind: "ConfigMap",
+					Name: "internal-ca",
+					Key:  "ca.crt",
+				},
+			},
+			wanted: &gopass_repository.Authentication{
+				Namespace: "test-namespace",
+				SecretRef: "git-credentials",
+				SecretKey: "token",
+				CaBundleRef: &gopass_repository.ResourceKeyReference{
+					Kind: "ConfigMap",
+					Name: "internal-ca",
+					Key:  "ca.crt",
+				},
+			},
+		},
+		{
+			name: "known hosts from Secret",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				UserName: "git",
+				KnownHostsRef: &gopassv1alpha1.ResourceKeyRefSpec{
+					Name: "known-hosts",
+					Key:  "known_hosts",
+				},
+			},
+			wanted: &gopass_repository.Authentication{
+				Namespace: "test-namespace",
+				Username:  "git",
+				KnownHostsRef: &gopass_repository.ResourceKeyReference{
+					Name: "known-hosts",
+					Key:  "known_hosts",
+				},
+			},
+		},
+		{
+			name: "insecure host key verification",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				UserName:              "git",
+				InsecureIgnoreHostKey: true,
+			},
+			wanted: &gopass_repository.Authentication{
+				Namespace:             "test-namespace",
+				Username:              "git",
+				InsecureIgnoreHostKey: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := createAuthentication("test-namespace", tt.spec)
+			if !proto.Equal(got, tt.wanted) {
+				t.Errorf("createAuthentication() = %v, wanted %v", got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestCreateGpgKeyReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   gopassv1alpha1.GopassRepositorySpec
+		wanted *gopass_repository.GpgKeyReference
+	}{
+		{
+			name: "gpg key without passphrase",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				GpgKeyRef: gopassv1alpha1.SecretKeyRefSpec{
+					Name: "gpg-key",
+					Key:  "private.key",
+				},
+			},
+			wanted: &gopass_repository.GpgKeyReference{
+				GpgKeyRef:    "gpg-key",
+				GpgKeyRefKey: "private.key",
+			},
+		},
+		{
+			name: "gpg key with passphrase",
+			spec: gopassv1alpha1.GopassRepositorySpec{
+				GpgKeyRef: gopassv1alpha1.SecretKeyRefSpec{
+					Name: "gpg-key",
+					Key:  "private.key",
+				},
+				GpgPassphraseRef: &gopassv1alpha1.SecretKeyRefSpec{
+					Name: "gpg-passphrase",
+					Key:  "passphrase",
+				},
+			},
+			wanted: &gopass_repository.GpgKeyReference{
+				GpgKeyRef:           "gpg-key",
+				GpgKeyRefKey:        "private.key",
+				GpgPassphraseRef:    "gpg-passphrase",
+				GpgPassphraseRefKey: "passphrase",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := createGpgKeyReference(tt.spec)
+			if !proto.Equal(got, tt.wanted) {
+				t.Errorf("createGpgKeyReference() = %v, wanted %v", got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestCreateMounts(t *testing.T) {
+	spec := gopassv1alpha1.GopassRepositorySpec{
+		UserName: "Henry.Dorsett.Case",
+		SecretKeyRef: gopassv1alpha1.SecretKeyRefSpec{
+			Name: "git-credentials",
+			Key:  "password",
+		},
+		Mounts: []gopassv1alpha1.MountSpec{
+			{
+				MountPoint:    "shared",
+				RepositoryURL: "https://example.com/shared.git",
+				Ref:           "v1.0.0",
+			},
+			{
+				MountPoint:    "team",
+				RepositoryURL: "git@example.com:team.git",
+				UserName:      "git",
+				SSHKeyRef: &gopassv1alpha1.SSHKeyRefSpec{
+					Name: "deploy-key",
+					Key:  "id_ed25519",
+				},
+			},
+		},
+	}
+
+	wanted := []*gopass_repository.Mount{
+		{
+			MountPoint:    "shared",
+			RepositoryURL: "https://example.com/shared.git",
+			Ref:           "v1.0.0",
+			Authentication: &gopass_repository.Authentication{
+				Namespace: "test-namespace",
+				Username:  "Henry.Dorsett.Case",
+				SecretRef: "git-credentials",
+				SecretKey: "password",
+			},
+		},
+		{
+			MountPoint:    "team",
+			RepositoryURL: "git@example.com:team.git",
+			Authentication: &gopass_repository.Authentication{
+				Namespace:    "test-namespace",
+				Username:     "git",
+				SshKeyRef:    "deploy-key",
+				SshKeyRefKey: "id_ed25519",
+			},
+		},
+	}
+
+	got := createMounts("test-namespace", spec)
+	if len(got) != len(wanted) {
+		t.Errorf("createMounts() = %v, wanted %v", got, wanted)
+		return
+	}
+	for i := range wanted {
+		if !proto.Equal(got[i], wanted[i]) {
+			t.Errorf("createMounts()[%d] = %v, wanted %v", i, got[i], wanted[i])
+		}
+	}
+}
+
+func TestCreateRepository(t *testing.T) {
+	namespacedName := types.NamespacedName{Namespace: "test-namespace", Name: "test-repository"}
+
+	got := createRepository(namespacedName, gopassv1alpha1.GopassRepositorySpec{
+		RepositoryURL:  "https://example.com/password-store.git",
+		Exclude:        []string{"**/personal/**"},
+		PrunePolicy:    "Keep",
+		DeletionPolicy: "Orphan",
+		SecretLabels:   map[string]string{"team": "a"},
+		Secrets: []gopassv1alpha1.SecretTargetSpec{
+			{
+				Name:        "team-a",
+				Prefix:      "team-a",
+				Kind:        "ConfigMap",
+				Annotations: map[string]string{"reloader.stakater.com/match": "true"},
+			},
+			{
+				Name: "registry",
+				Type: "kubernetes.io/dockerconfigjson",
+				Registries: []gopassv1alpha1.RegistryCredentialsSpec{
+					{Server: "registry.example.com", Path: "team-a/registry", UsernameField: "login"},
+				},
+			},
+			{
+				Name: "database",
+				Data: []gopassv1alpha1.KeyMappingSpec{
+					{Key: "DB_PASSWORD", Path: "team-a/prod/database"},
+					{Key: "DB_USER", Path: "team-a/prod/database", Field: "username"},
+				},
+				IncludeFields: true,
+				Templates:     map[string]string{"DATABASE_URL": `{{ gopass "team-a/prod/database" }}`},
+			},
+		},
+	})
+
+	wanted := &gopass_repository.Repository{
+		RepositoryURL: "https://example.com/password-store.git",
+		SecretName: &gopass_repository.NamespacedName{
+			Namespace: "test-namespace",
+			Name:      "test-repository",
+		},
+		Exclude:        []string{"**/personal/**"},
+		PrunePolicy:    "Keep",
+		DeletionPolicy: "Orphan",
+		Labels:         map[string]string{"team": "a"},
+		Secrets: []*gopass_repository.SecretTarget{
+			{
+				Name:        &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "team-a"},
+				Prefix:      "team-a",
+				Kind:        "ConfigMap",
+				Annotations: map[string]string{"reloader.stakater.com/match": "true"},
+			},
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "registry"},
+				Type: "kubernetes.io/dockerconfigjson",
+				Registries: []*gopass_repository.RegistryCredentials{
+					{Server: "registry.example.com", Path: "team-a/registry", UsernameField: "login"},
+				},
+			},
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "database"},
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "DB_PASSWORD", Path: "team-a/prod/database"},
+					{Key: "DB_USER", Path: "team-a/prod/database", Field: "username"},
+				},
+				IncludeFields: true,
+				Templates:     map[string]string{"DATABASE_URL": `{{ gopass "team-a/prod/database" }}`},
+			},
+		},
+	}
+
+	if !proto.Equal(got, wanted) {
+		t.Errorf("createRepository() = %v, wanted %v", got, wanted)
+	}
+}
+
+func TestRemovePreviousRepository(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusURL      string
+		wantedRemovals []string
+	}{
+		{name: "not initialized yet", statusURL: "", wantedRemovals: []string{}},
+		{name: "URL unchanged", statusURL: "https://example.com/password-store.git", wantedRemovals: []string{}},
+		{name: "URL changed", statusURL: "https://example.com/old-password-store.git", wantedRemovals: []string{"https://example.com/old-password-store.git"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceClient := NewTestRepositoryServiceClient()
+			gopassRepository := &gopassv1alpha1.GopassRepository{
+				Spec:   gopassv1alpha1.GopassRepositorySpec{RepositoryURL: "https://example.com/password-store.git"},
+				Status: gopassv1alpha1.GopassRepositoryStatus{RepositoryURL: tt.statusURL},
+			}
+
+			removePreviousRepository(context.Background(), logr_testing.NullLogger{}, serviceClient, gopassRepository)
+
+			if !reflect.DeepEqual(serviceClient.Calls["RemoveRepository"], tt.wantedRemovals) {
+				t.Errorf("RemoveRepository was called with %v, wanted %v", serviceClient.Calls["RemoveRepository"], tt.wantedRemovals)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reasonDeploymentFailed     = "DeploymentFailed"
+	reasonDeploymentInProgress = "DeploymentInProgress"
+	reasonServiceNotReady      = "ServiceNotReady"
+	reasonServerReady          = "ServerReady"
+	reasonInitializationFailed = "InitializationFailed"
+	reasonInitialized          = "Initialized"
+	reasonUpdateFailed         = "UpdateFailed"
+	reasonSyncFailed           = "SyncFailed"
+	reasonSynced               = "Synced"
+	reasonInvalidSpec          = "InvalidSpec"
+	reasonHostKeyNotVerified   = "HostKeyNotVerified"
+)
+
+func setCondition(repository *gopassv1alpha1.GopassRepository, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&repository.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: repository.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setFailedCondition marks the given condition and the Ready condition as failed and records the error.
+func setFailedCondition(repository *gopassv1alpha1.GopassRepository, conditionType string, reason string, err error) {
+	setCondition(repository, conditionType, metav1.ConditionFalse, reason, err.Error())
+	setCondition(repository, gopassv1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+	repository.Status.LastError = err.Error()
+}
+
+// setSyncFailedStatus records a failed sync of the repository along with the colliding keys, template errors and stale
+// keys the repository server found before it failed.
+func setSyncFailedStatus(repository *gopassv1alpha1.GopassRepository, err error) {
+	setFailedCondition(repository, gopassv1alpha1.ConditionSynced, reasonSyncFailed, err)
+	reports := reportsOfError(err)
+	repository.Status.Collisions = collisionStatus(reports.Collisions)
+	repository.Status.TemplateErrors = templateErrorStatus(reports.TemplateErrors)
+	repository.Status.StaleKeys = staleKeyStatus(reports.StaleKeys)
+}
+
+// reportsOfError returns the colliding keys, template errors and stale keys the repository server attached to the error
+// of a failed sync.
+func reportsOfError(err error) *gopass_repository.RepositoryResponse {
+	reports := &gopass_repository.RepositoryResponse{}
+	for _, detail := range status.Convert(err).Details() {
+		switch report := detail.(type) {
+		case *gopass_repository.KeyCollision:
+			reports.Collisions = append(reports.Collisions, report)
+		case *gopass_repository.TemplateError:
+			reports.TemplateErrors = append(reports.TemplateErrors, report)
+		case *gopass_repository.StaleKey:
+			reports.StaleKeys = append(reports.StaleKeys, report)
+		}
+	}
+	return reports
+}
+
+// setSyncedStatus records a successful sync of the repository.
+func setSyncedStatus(repository *gopassv1alpha1.GopassRepository, response *gopass_repository.RepositoryResponse) {
+	now := metav1.Now()
+	repository.Status.LastSyncTime = &now
+	repository.Status.LastError = ""
+	if response != nil {
+		repository.Status.LastSyncedCommit = response.CommitHash
+		repository.Status.SyncedEntries = response.SyncedEntries
+		repository.Status.Collisions = collisionStatus(response.Collisions)
+		repository.Status.TemplateErrors = templateErrorStatus(response.TemplateErrors)
+		repository.Status.StaleKeys = staleKeyStatus(response.StaleKeys)
+		repository.Status.UpdatedSecrets = secretNames(response.Updated)
+		repository.Status.UnchangedSecrets = secretNames(response.Unchanged)
+		repository.Status.UntargetedSecrets = secretNames(response.Untargeted)
+	}
+
+	message := fmt.Sprintf("entries have been synced, %d secrets updated, %d unchanged", len(repository.Status.UpdatedSecrets), len(repository.Status.UnchangedSecrets))
+	if len(repository.Status.Collisions) > 0 {
+		message = fmt.Sprintf("%s, %d keys are produced by several entries", message, len(repository.Status.Collisions))
+	}
+	if len(repository.Status.TemplateErrors) > 0 {
+		message = fmt.Sprintf("%s, %d templates could not be rendered", message, len(repository.Status.TemplateErrors))
+	}
+	if len(repository.Status.StaleKeys) > 0 {
+		message = fmt.Sprintf("%s, %d keys are stale", message, len(repository.Status.StaleKeys))
+	}
+	if len(repository.Status.UntargetedSecrets) > 0 {
+		message = fmt.Sprintf("%s, %d secrets are no longer targeted", message, len(repository.Status.UntargetedSecrets))
+	}
+	setCondition(repository, gopassv1alpha1.ConditionSynced, metav1.ConditionTrue, reasonSynced, message)
+	setCondition(repository, gopassv1alpha1.ConditionReady, metav1.ConditionTrue, reasonSynced, "entries have been synced")
+}
+
+func collisionStatus(collisions []*gopass_repository.KeyCollision) []gopassv1alpha1.KeyCollisionStatus {
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	status := make([]gopassv1alpha1.KeyCollisionStatus, 0, len(collisions))
+	for _, collision := range collisions {
+		status = append(status, gopassv1alpha1.KeyCollisionStatus{
+			Secret:  collision.SecretName.GetName(),
+			Key:     collision.Key,
+			Entries: collision.Entries,
+		})
+	}
+	return status
+}
+
+func templateErrorStatus(templateErrors []*gopass_repository.TemplateError) []gopassv1alpha1.TemplateErrorStatus {
+	if len(templateErrors) == 0 {
+		return nil
+	}
+
+	status := make([]gopassv1alpha1.TemplateErrorStatus, 0, len(templateErrors))
+	for _, templateError := range templateErrors {
+		status = append(status, gopassv1alpha1.TemplateErrorStatus{
+			Secret:  templateError.SecretName.GetName(),
+			Key:     templateError.Key,
+			Message: templateError.Message,
+		})
+	}
+	return status
+}
+
+func secretNames(names []*gopass_repository.NamespacedName) []string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, name.GetName())
+	}
+	return result
+}
+
+func staleKeyStatus(staleKeys []*gopass_repository.StaleKey) []gopassv1alpha1.StaleKeyStatus {
+	if len(staleKeys) == 0 {
+		return nil
+	}
+
+	status := make([]gopassv1alpha1.StaleKeyStatus, 0, len(staleKeys))
+	for _, staleKey := range staleKeys {
+		status = append(status, gopassv1alpha1.StaleKeyStatus{
+			Secret: staleKey.SecretName.GetName(),
+			Key:    staleKey.Key,
+		})
+	}
+	return status
+}
+
+// setHostKeyCondition warns about a repository whose host key is not verified.
+func setHostKeyCondition(repository *gopassv1alpha1.GopassRepository) {
+	if repository.Spec.InsecureIgnoreHostKey && repository.Spec.KnownHostsRef == nil {
+		setCondition(repository, gopassv1alpha1.ConditionInsecureHostKey, metav1.ConditionTrue, reasonHostKeyNotVerified, "host key verification is disabled by insecureIgnoreHostKey")
+		return
+	}
+	meta.RemoveStatusCondition(&repository.Status.Conditions, gopassv1alpha1.ConditionInsecureHostKey)
+}
+
+func (r *GopassRepositoryReconciler) updateStatus(ctx context.Context, repository *gopassv1alpha1.GopassRepository, base *gopassv1alpha1.GopassRepository) {
+	err := r.Status().Patch(ctx, repository, client.MergeFrom(base))
+	if err != nil {
+		r.Log.Error(err, "unable to update status of GopassRepository")
+	}
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"fmt"
+	gopassv1alpha1 "github.com/mdreem/gopass-operator/controller/api/v1alpha1"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"testing"
+)
+
+func TestSetFailedCondition(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{}
+
+	setFailedCondition(repository, gopassv1alpha1.ConditionSynced, reasonSyncFailed, fmt.Errorf("some error"))
+
+	for _, conditionType := range []string{gopassv1alpha1.ConditionSynced, gopassv1alpha1.ConditionReady} {
+		condition := meta.FindStatusCondition(repository.Status.Conditions, conditionType)
+		if condition == nil {
+			t.Errorf("condition '%s' not found", conditionType)
+			continue
+		}
+		if condition.Status != metav1.ConditionFalse {
+			t.Errorf("condition '%s' has status '%s', wanted '%s'", conditionType, condition.Status, metav1.ConditionFalse)
+		}
+		if condition.Reason != reasonSyncFailed {
+			t.Errorf("condition '%s' has reason '%s', wanted '%s'", conditionType, condition.Reason, reasonSyncFailed)
+		}
+	}
+
+	if repository.Status.LastError != "some error" {
+		t.Errorf("lastError was '%s', wanted '%s'", repository.Status.LastError, "some error")
+	}
+}
+
+func TestSetSyncFailedStatus(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{}
+
+	secretName := &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "team-a"}
+	syncStatus, err := status.New(codes.FailedPrecondition, "colliding keys in secret 'test-namespace/team-a'").WithDetails(
+		&gopass_repository.KeyCollision{SecretName: secretName, Key: "db-password", Entries: []string{"db-password", "db_password"}},
+		&gopass_repository.TemplateError{SecretName: secretName, Key: "DATABASE_URL", Message: "entry not found"},
+		&gopass_repository.StaleKey{SecretName: secretName, Key: "removed"},
+	)
+	if err != nil {
+		t.Errorf("unable to attach collision: %v", err)
+		return
+	}
+
+	setSyncFailedStatus(repository, syncStatus.Err())
+
+	if meta.IsStatusConditionTrue(repository.Status.Conditions, gopassv1alpha1.ConditionSynced) {
+		t.Errorf("condition '%s' is true", gopassv1alpha1.ConditionSynced)
+	}
+	wantedCollisions := []gopassv1alpha1.KeyCollisionStatus{
+		{Secret: "team-a", Key: "db-password", Entries: []string{"db-password", "db_password"}},
+	}
+	if !reflect.DeepEqual(repository.Status.Collisions, wantedCollisions) {
+		t.Errorf("collisions were %v, wanted %v", repository.Status.Collisions, wantedCollisions)
+	}
+	wantedTemplateErrors := []gopassv1alpha1.TemplateErrorStatus{
+		{Secret: "team-a", Key: "DATABASE_URL", Message: "entry not found"},
+	}
+	if !reflect.DeepEqual(repository.Status.TemplateErrors, wantedTemplateErrors) {
+		t.Errorf("template errors were %v, wanted %v", repository.Status.TemplateErrors, wantedTemplateErrors)
+	}
+	if len(repository.Status.StaleKeys) != 1 || repository.Status.StaleKeys[0].Key != "removed" {
+		t.Errorf("stale keys were %v, wanted 'removed'", repository.Status.StaleKeys)
+	}
+
+	setSyncFailedStatus(repository, fmt.Errorf("some error"))
+	if repository.Status.Collisions != nil || repository.Status.TemplateErrors != nil || repository.Status.StaleKeys != nil {
+		t.Errorf("reports were %v, %v, %v, wanted none", repository.Status.Collisions, repository.Status.TemplateErrors, repository.Status.StaleKeys)
+	}
+}
+
+func TestSetSyncedStatus(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{
+		Status: gopassv1alpha1.GopassRepositoryStatus{
+			LastError: "some previous error",
+		},
+	}
+
+	setSyncedStatus(repository, &gopass_repository.RepositoryResponse{
+		Successful:    true,
+		CommitHash:    "0123456789abcdef",
+		SyncedEntries: 3,
+		Collisions: []*gopass_repository.KeyCollision{
+			{
+				SecretName: &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "team-a"},
+				Key:        "db-password",
+				Entries:    []string{"db-password", "db_password"},
+			},
+		},
+		TemplateErrors: []*gopass_repository.TemplateError{
+			{
+				SecretName: &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "team-a"},
+				Key:        "DATABASE_URL",
+				Message:    "entry 'does/not/exist' not found",
+			},
+		},
+		StaleKeys: []*gopass_repository.StaleKey{
+			{
+				SecretName: &gopass_repository.NamespacedName{Namespace: "test-namespace", Name: "team-a"},
+				Key:        "removed",
+			},
+		},
+		Updated: []*gopass_repository.NamespacedName{
+			{Namespace: "test-namespace", Name: "team-a"},
+		},
+		Unchanged: []*gopass_repository.NamespacedName{
+			{Namespace: "test-namespace", Name: "team-b"},
+			{Namespace: "test-namespace", Name: "team-c"},
+		},
+		Untargeted: []*gopass_repository.NamespacedName{
+			{Namespace: "test-namespace", Name: "team-d"},
+		},
+	})
+
+	if !meta.IsStatusConditionTrue(repository.Status.Conditions, gopassv1alpha1.ConditionSynced) {
+		t.Errorf("condition '%s' is not true", gopassv1alpha1.ConditionSynced)
+	}
+	if !meta.IsStatusConditionTrue(repository.Status.Conditions, gopassv1alpha1.ConditionReady) {
+		t.Errorf("condition '%s' is not true", gopassv1alpha1.ConditionReady)
+	}
+	if repository.Status.LastSyncedCommit != "0123456789abcdef" {
+		t.Errorf("lastSyncedCommit was '%s', wanted '%s'", repository.Status.LastSyncedCommit, "0123456789abcdef")
+	}
+	if repository.Status.SyncedEntries != 3 {
+		t.Errorf("syncedEntries was '%d', wanted '%d'", repository.Status.SyncedEntries, 3)
+	}
+	wantedCollisions := []gopassv1alpha1.KeyCollisionStatus{
+		{Secret: "team-a", Key: "db-password", Entries: []string{"db-password", "db_password"}},
+	}
+	if !reflect.DeepEqual(repository.Status.Collisions, wantedCollisions) {
+		t.Errorf("collisions were %v, wanted %v", repository.Status.Collisions, wantedCollisions)
+	}
+	wantedTemplateErrors := []gopassv1alpha1.TemplateErrorStatus{
+		{Secret: "team-a", Key: "DATABASE_URL", Message: "entry 'does/not/exist' not found"},
+	}
+	if !reflect.DeepEqual(repository.Status.TemplateErrors, wantedTemplateErrors) {
+		t.Errorf("template errors were %v, wanted %v", repository.Status.TemplateErrors, wantedTemplateErrors)
+	}
+	if !reflect.DeepEqual(repository.Status.UpdatedSecrets, []string{"team-a"}) {
+		t.Errorf("updated secrets were %v, wanted %v", repository.Status.UpdatedSecrets, []string{"team-a"})
+	}
+	if !reflect.DeepEqual(repository.Status.UnchangedSecrets, []string{"team-b", "team-c"}) {
+		t.Errorf("unchanged secrets were %v, wanted %v", repository.Status.UnchangedSecrets, []string{"team-b", "team-c"})
+	}
+	if !reflect.DeepEqual(repository.Status.UntargetedSecrets, []string{"team-d"}) {
+		t.Errorf("untargeted secrets were %v, wanted %v", repository.Status.UntargetedSecrets, []string{"team-d"})
+	}
+	wantedStaleKeys := []gopassv1alpha1.StaleKeyStatus{{Secret: "team-a", Key: "removed"}}
+	if !reflect.DeepEqual(repository.Status.StaleKeys, wantedStaleKeys) {
+		t.Errorf("stale keys were %v, wanted %v", repository.Status.StaleKeys, wantedStaleKeys)
+	}
+	if repository.Status.LastSyncTime == nil {
+		t.Errorf("lastSyncTime was not set")
+	}
+	if repository.Status.LastError != "" {
+		t.Errorf("lastError was '%s', wanted it to be empty", repository.Status.LastError)
+	}
+}
+
+func TestSetHostKeyCondition(t *testing.T) {
+	repository := &gopassv1alpha1.GopassRepository{
+		Spec: gopassv1alpha1.GopassRepositorySpec{
+			InsecureIgnoreHostKey: true,
+		},
+	}
+
+	setHostKeyCondition(repository)
+	if !meta.IsStatusConditionTrue(repository.Status.Conditions, gopassv1alpha1.ConditionInsecureHostKey) {
+		t.Errorf("condition '%s' is not true", gopassv1alpha1.ConditionInsecureHostKey)
+	}
+
+	repository.Spec.KnownHostsRef = &gopassv1alpha1.ResourceKeyRefSpec{
+		Name: "known-hosts",
+		Key:  "known_hosts",
+	}
+
+	setHostKeyCondition(repository)
+	if meta.FindStatusCondition(repository.Status.Conditions, gopassv1alpha1.ConditionInsecureHostKey) != nil {
+		t.Errorf("condition '%s' was not removed", gopassv1alpha1.ConditionInsecureHostKey)
+	}
+}
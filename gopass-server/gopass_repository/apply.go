@@ -0,0 +1,295 @@
+package gopass_repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"log"
+	"sort"
+	"strings"
+)
+
+// fieldManager owns the keys, labels and annotations written by the operator. Fields of other managers are preserved.
+const fieldManager = "gopass-operator"
+
+// defaultLegacyFieldManager owns the fields written with Create and Update before server-side apply was used. The API
+// server derived it from the user agent of the repository server, which is the name of its binary (bin/gopass_server in
+// the Dockerfile). Servers built under another name have to configure their manager in LegacyFieldManagers.
+const defaultLegacyFieldManager = "gopass_server"
+
+// contentHashAnnotation contains the hash of the content last written by the operator.
+const contentHashAnnotation = "gopass.operator/content-hash"
+
+// contentHash identifies everything the operator applies to the resource of a target.
+func contentHash(target secretTarget, data map[string]string) string {
+	hash := sha256.New()
+	write := func(values map[string]string) {
+		for _, key := range sortedFieldNames(values) {
+			_, _ = fmt.Fprintf(hash, "%d:%s%d:%s", len(key), key, len(values[key]), values[key])
+		}
+		_, _ = hash.Write([]byte{0})
+	}
+
+	_, _ = fmt.Fprintf(hash, "%s\x00%s\x00", target.kind, target.secretType)
+	write(data)
+	write(target.labels)
+	write(target.annotations)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// unchanged reports whether a resource already contains the content, judged by its hash annotation, its data and the
+// labels and annotations requested by the target. Labels and annotations removed or edited by others are applied again.
+func unchanged(existing metav1.ObjectMeta, existingData map[string]string, target secretTarget, hash string, data map[string]string) bool {
+	return existing.Annotations[contentHashAnnotation] == hash &&
+		containsAll(existingData, data) &&
+		containsAll(existing.Labels, target.labels) &&
+		containsAll(existing.Annotations, target.annotations)
+}
+
+// containsAll reports whether existing contains all keys of wanted with the same values.
+func containsAll(existing map[string]string, wanted map[string]string) bool {
+	for key, value := range wanted {
+		existingValue, ok := existing[key]
+		if !ok || existingValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// secretData returns the data of a Secret as strings.
+func secretData(secret *corev1.Secret) map[string]string {
+	data := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	return data
+}
+
+// applyConfiguration builds the partial object sent by server-side apply. It only contains the fields owned by the operator.
+func applyConfiguration(kind string, target secretTarget, hash string) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"name":        target.name.Name,
+		"namespace":   target.name.Namespace,
+		"annotations": mergeMaps(target.annotations, map[string]string{contentHashAnnotation: hash}),
+	}
+	if len(target.labels) > 0 {
+		metadata["labels"] = target.labels
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata":   metadata,
+	}
+}
+
+func applyPatch(configuration map[string]interface{}) ([]byte, metav1.PatchOptions, error) {
+	force := true
+	patch, err := json.Marshal(configuration)
+	return patch, metav1.PatchOptions{FieldManager: fieldManager, Force: &force}, err
+}
+
+func (r *RepositoryServer) applySecret(ctx context.Context, target secretTarget, secretMap map[string]string, hash string) error {
+	data := make(map[string][]byte, len(secretMap))
+	for key, value := range secretMap {
+		data[key] = []byte(value)
+	}
+
+	configuration := applyConfiguration("Secret", target, hash)
+	configuration["type"] = target.secretType
+	configuration["data"] = data
+
+	patch, options, err := applyPatch(configuration)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.KubernetesClient.CoreV1().Secrets(target.name.Namespace).Patch(ctx, target.name.Name, types.ApplyPatchType, patch, options)
+	return err
+}
+
+func (r *RepositoryServer) applyConfigMap(ctx context.Context, target secretTarget, data map[string]string, hash string) error {
+	configuration := applyConfiguration("ConfigMap", target, hash)
+	configuration["data"] = data
+
+	patch, options, err := applyPatch(configuration)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.KubernetesClient.CoreV1().ConfigMaps(target.name.Namespace).Patch(ctx, target.name.Name, types.ApplyPatchType, patch, options)
+	return err
+}
+
+// managedKeys returns the keys of data owned by the operator according to the managed fields of a resource.
+func managedKeys(managedFields []metav1.ManagedFieldsEntry, legacyManagers []string) []string {
+	keys := make([]string, 0)
+	for _, entry := range managedFields {
+		if !ownedByOperator(entry, legacyManagers) || entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := make(map[string]map[string]interface{})
+		err := json.Unmarshal(entry.FieldsV1.Raw, &fields)
+		if err != nil {
+			log.Printf("unable to parse managed fields: %v\n", err)
+			continue
+		}
+
+		for field := range fields["f:data"] {
+			if strings.HasPrefix(field, "f:") {
+				keys = append(keys, strings.TrimPrefix(field, "f:"))
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ownedByOperator reports whether the fields of the entry have been written by the operator, either applied or written
+// by the repository server before server-side apply was used.
+func ownedByOperator(entry metav1.ManagedFieldsEntry, legacyManagers []string) bool {
+	return (entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply) ||
+		writtenByLegacyManager(entry, legacyManagers)
+}
+
+// writtenByLegacyManager reports whether the fields of the entry have been written with Update by one of the managers
+// used before server-side apply.
+func writtenByLegacyManager(entry metav1.ManagedFieldsEntry, legacyManagers []string) bool {
+	if entry.Operation != metav1.ManagedFieldsOperationUpdate {
+		return false
+	}
+	for _, manager := range legacyManagers {
+		if entry.Manager == manager {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyFieldManagers returns the configured managers used before server-side apply, or the default one.
+func (r *RepositoryServer) legacyFieldManagers() []string {
+	if len(r.LegacyFieldManagers) == 0 {
+		return []string{defaultLegacyFieldManager}
+	}
+	return r.LegacyFieldManagers
+}
+
+// handOverPatch returns a JSON patch transferring the fields written before server-side apply was used to the field
+// manager of the operator. Otherwise fields dropped from the apply configuration would still be owned by the previous
+// manager and never be removed. It returns nil if there is nothing to hand over.
+func handOverPatch(meta metav1.ObjectMeta, legacyManagers []string) ([]byte, error) {
+	managedFields := make([]metav1.ManagedFieldsEntry, 0, len(meta.ManagedFields))
+	fields := make(map[string]interface{})
+	var legacyTime *metav1.Time
+	handOver := false
+
+	for _, entry := range meta.ManagedFields {
+		if !writtenByLegacyManager(entry, legacyManagers) {
+			managedFields = append(managedFields, entry)
+			continue
+		}
+
+		handOver = true
+		legacyTime = entry.Time
+		err := addFields(fields, entry.FieldsV1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !handOver {
+		return nil, nil
+	}
+
+	operatorEntry := -1
+	for i, entry := range managedFields {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			operatorEntry = i
+			err := addFields(fields, entry.FieldsV1)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if operatorEntry >= 0 {
+		managedFields[operatorEntry].FieldsV1 = &metav1.FieldsV1{Raw: raw}
+	} else {
+		managedFields = append(managedFields, metav1.ManagedFieldsEntry{
+			Manager:    fieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "v1",
+			Time:       legacyTime,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		})
+	}
+
+	// the managed fields are only replaced if nobody changed the resource in the meantime
+	return json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": meta.ResourceVersion},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+}
+
+// addFields adds the field set of a managed fields entry to fields.
+func addFields(fields map[string]interface{}, fieldsV1 *metav1.FieldsV1) error {
+	if fieldsV1 == nil {
+		return nil
+	}
+
+	entryFields := make(map[string]interface{})
+	err := json.Unmarshal(fieldsV1.Raw, &entryFields)
+	if err != nil {
+		return err
+	}
+	mergeFields(fields, entryFields)
+	return nil
+}
+
+// mergeFields adds the field set of source to target.
+func mergeFields(target map[string]interface{}, source map[string]interface{}) {
+	for field, value := range source {
+		sourceFields, ok := value.(map[string]interface{})
+		targetFields, targetOk := target[field].(map[string]interface{})
+		if ok && targetOk {
+			mergeFields(targetFields, sourceFields)
+			continue
+		}
+		target[field] = value
+	}
+}
+
+// handOverSecret transfers the fields of the Secret written before server-side apply was used to the operator.
+func (r *RepositoryServer) handOverSecret(ctx context.Context, secret *corev1.Secret) error {
+	patch, err := handOverPatch(secret.ObjectMeta, r.legacyFieldManagers())
+	if err != nil || patch == nil {
+		return err
+	}
+
+	log.Printf("handing over fields of secret map '%s/%s' to '%s'\n", secret.Namespace, secret.Name, fieldManager)
+	_, err = r.KubernetesClient.CoreV1().Secrets(secret.Namespace).Patch(ctx, secret.Name, types.JSONPatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	return err
+}
+
+// handOverConfigMap transfers the fields of the ConfigMap written before server-side apply was used to the operator.
+func (r *RepositoryServer) handOverConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	patch, err := handOverPatch(configMap.ObjectMeta, r.legacyFieldManagers())
+	if err != nil || patch == nil {
+		return err
+	}
+
+	log.Printf("handing over fields of config map '%s/%s' to '%s'\n", configMap.Namespace, configMap.Name, fieldManager)
+	_, err = r.KubernetesClient.CoreV1().ConfigMaps(configMap.Namespace).Patch(ctx, configMap.Name, types.JSONPatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	return err
+}
@@ -0,0 +1,670 @@
+package gopass_repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gopasspw/gopass/pkg/gopass/apimock"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	clientgotesting "k8s.io/client-go/testing"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// applyClientset is a fake clientset recording the server-side applies of Secrets and ConfigMaps. The fake does not
+// merge applies like the API server does, it stores the applied object as it is sent. Tests therefore assert on the
+// recorded apply configurations, which only have to contain the fields owned by the operator.
+type applyClientset struct {
+	*fake.Clientset
+	lock    sync.Mutex
+	applies []appliedPatch
+}
+
+type appliedPatch struct {
+	resource      string
+	name          string
+	configuration map[string]interface{}
+	options       metav1.PatchOptions
+}
+
+func newApplyClientset(objects ...runtime.Object) *applyClientset {
+	clientset := fake.NewSimpleClientset(objects...)
+
+	clientset.PrependReactor("patch", "*", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(clientgotesting.PatchAction)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		var applied runtime.Object
+		switch patchAction.GetResource().Resource {
+		case "secrets":
+			applied = &corev1.Secret{}
+		case "configmaps":
+			applied = &corev1.ConfigMap{}
+		default:
+			return false, nil, nil
+		}
+		if err := json.Unmarshal(patchAction.GetPatch(), applied); err != nil {
+			return true, nil, err
+		}
+
+		err := clientset.Tracker().Update(patchAction.GetResource(), applied, patchAction.GetNamespace())
+		if errors.IsNotFound(err) {
+			err = clientset.Tracker().Create(patchAction.GetResource(), applied, patchAction.GetNamespace())
+		}
+		return true, applied, err
+	})
+
+	return &applyClientset{Clientset: clientset}
+}
+
+func (a *applyClientset) CoreV1() corev1client.CoreV1Interface {
+	return &applyCoreV1{CoreV1Interface: a.Clientset.CoreV1(), clientset: a}
+}
+
+func (a *applyClientset) record(resource string, name string, patchType types.PatchType, patch []byte, options metav1.PatchOptions) error {
+	if patchType != types.ApplyPatchType {
+		return nil
+	}
+
+	configuration := make(map[string]interface{})
+	err := json.Unmarshal(patch, &configuration)
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.applies = append(a.applies, appliedPatch{resource: resource, name: name, configuration: configuration, options: options})
+	return nil
+}
+
+// lastApply returns the last apply of the resource with the given name.
+func (a *applyClientset) lastApply(t *testing.T, resource string, name string) appliedPatch {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for i := len(a.applies) - 1; i >= 0; i-- {
+		if a.applies[i].resource == resource && a.applies[i].name == name {
+			return a.applies[i]
+		}
+	}
+	t.Fatalf("%s '%s' was not applied", resource, name)
+	return appliedPatch{}
+}
+
+type applyCoreV1 struct {
+	corev1client.CoreV1Interface
+	clientset *applyClientset
+}
+
+func (c *applyCoreV1) Secrets(namespace string) corev1client.SecretInterface {
+	return &applySecrets{SecretInterface: c.CoreV1Interface.Secrets(namespace), clientset: c.clientset}
+}
+
+func (c *applyCoreV1) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &applyConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), clientset: c.clientset}
+}
+
+type applySecrets struct {
+	corev1client.SecretInterface
+	clientset *applyClientset
+}
+
+func (s *applySecrets) Patch(ctx context.Context, name string, patchType types.PatchType, patch []byte, options metav1.PatchOptions, subresources ...string) (*corev1.Secret, error) {
+	err := s.clientset.record("secrets", name, patchType, patch, options)
+	if err != nil {
+		return nil, err
+	}
+	return s.SecretInterface.Patch(ctx, name, patchType, patch, options, subresources...)
+}
+
+type applyConfigMaps struct {
+	corev1client.ConfigMapInterface
+	clientset *applyClientset
+}
+
+func (c *applyConfigMaps) Patch(ctx context.Context, name string, patchType types.PatchType, patch []byte, options metav1.PatchOptions, subresources ...string) (*corev1.ConfigMap, error) {
+	err := c.clientset.record("configmaps", name, patchType, patch, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.ConfigMapInterface.Patch(ctx, name, patchType, patch, options, subresources...)
+}
+
+// assertApplied checks the apply is forced by the field manager of the operator and sends exactly the wanted configuration.
+func assertApplied(t *testing.T, applied appliedPatch, wanted map[string]interface{}) {
+	if applied.options.FieldManager != fieldManager {
+		t.Errorf("%s '%s' was applied by '%s', wanted '%s'", applied.resource, applied.name, applied.options.FieldManager, fieldManager)
+	}
+	if applied.options.Force == nil || !*applied.options.Force {
+		t.Errorf("%s '%s' was applied without force", applied.resource, applied.name)
+	}
+	if !reflect.DeepEqual(applied.configuration, wanted) {
+		t.Errorf("%s '%s' was applied with %v, wanted %v", applied.resource, applied.name, applied.configuration, wanted)
+	}
+}
+
+// appliedSecretData returns the decoded data sent by the apply of a Secret.
+func appliedSecretData(t *testing.T, applied appliedPatch) map[string]string {
+	data := make(map[string]string)
+	encodedData, _ := applied.configuration["data"].(map[string]interface{})
+	for key, value := range encodedData {
+		decoded, err := base64.StdEncoding.DecodeString(value.(string))
+		if err != nil {
+			t.Fatalf("unable to decode key '%s' of secret '%s': %v", key, applied.name, err)
+		}
+		data[key] = string(decoded)
+	}
+	return data
+}
+
+func managedFieldsEntry(manager string, keys []string) metav1.ManagedFieldsEntry {
+	data := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		data["f:"+key] = map[string]interface{}{}
+	}
+	raw, _ := json.Marshal(map[string]interface{}{"f:data": data})
+
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: raw},
+	}
+}
+
+func TestManagedKeys(t *testing.T) {
+	managedFields := []metav1.ManagedFieldsEntry{
+		managedFieldsEntry(fieldManager, []string{"b", "a"}),
+		managedFieldsEntry("kubectl", []string{"foreign"}),
+	}
+
+	keys := managedKeys(managedFields, []string{defaultLegacyFieldManager})
+	wanted := []string{"a", "b"}
+	if !reflect.DeepEqual(keys, wanted) {
+		t.Errorf("managedKeys() = %v, wanted %v", keys, wanted)
+	}
+}
+
+func TestUpdateAllPasswordsAppliesOnlyOwnedFields(t *testing.T) {
+	store := apimock.New()
+	err := store.Set(context.Background(), "database", &apimock.Secret{Buf: []byte("database password")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+
+	kubernetesClient := newApplyClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testNamespace",
+			Name:        "someSecret",
+			Labels:      map[string]string{"app.kubernetes.io/instance": "argo"},
+			Annotations: map[string]string{"reloader.stakater.com/match": "true"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(fieldManager, []string{"removed"}),
+				managedFieldsEntry("kubectl", []string{"foreign"}),
+			},
+		},
+		Data: map[string][]byte{
+			"removed": []byte("old password"),
+			"foreign": []byte("added by someone else"),
+		},
+	})
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	_, err = r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+		Labels:        map[string]string{"team": "a"},
+		Annotations:   map[string]string{"owner": "team-a"},
+	})
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+
+	applied := kubernetesClient.lastApply(t, "secrets", "someSecret")
+	metadata := applied.configuration["metadata"].(map[string]interface{})
+	hash := metadata["annotations"].(map[string]interface{})[contentHashAnnotation]
+	assertApplied(t, applied, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "someSecret",
+			"namespace": "testNamespace",
+			"labels": map[string]interface{}{
+				"team":                   "a",
+				repositoryNameLabel:      "someSecret",
+				repositoryNamespaceLabel: "testNamespace",
+			},
+			"annotations": map[string]interface{}{"owner": "team-a", contentHashAnnotation: hash},
+		},
+		"type": "Opaque",
+		"data": map[string]interface{}{"database": "ZGF0YWJhc2UgcGFzc3dvcmQ="},
+	})
+}
+
+func TestApplySecretSendsOwnedFields(t *testing.T) {
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{KubernetesClient: kubernetesClient}
+
+	target := secretTarget{
+		name:       types.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+		secretType: corev1.SecretTypeOpaque,
+		labels:     map[string]string{"team": "a"},
+	}
+	err := r.applySecret(context.Background(), target, map[string]string{"key": "value"}, "0123")
+	if err != nil {
+		t.Errorf("applySecret() error = %v", err)
+		return
+	}
+
+	assertApplied(t, kubernetesClient.lastApply(t, "secrets", "someSecret"), map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":        "someSecret",
+			"namespace":   "testNamespace",
+			"labels":      map[string]interface{}{"team": "a"},
+			"annotations": map[string]interface{}{contentHashAnnotation: "0123"},
+		},
+		"type": "Opaque",
+		"data": map[string]interface{}{"key": "dmFsdWU="},
+	})
+}
+
+func TestApplyConfigMapSendsOwnedFields(t *testing.T) {
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{KubernetesClient: kubernetesClient}
+
+	target := secretTarget{
+		name:        types.NamespacedName{Namespace: "testNamespace", Name: "config"},
+		kind:        targetKindConfigMap,
+		annotations: map[string]string{"owner": "team-a"},
+	}
+	err := r.applyConfigMap(context.Background(), target, map[string]string{"ENDPOINT": "https://api.example.com"}, "0123")
+	if err != nil {
+		t.Errorf("applyConfigMap() error = %v", err)
+		return
+	}
+
+	assertApplied(t, kubernetesClient.lastApply(t, "configmaps", "config"), map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":        "config",
+			"namespace":   "testNamespace",
+			"annotations": map[string]interface{}{"owner": "team-a", contentHashAnnotation: "0123"},
+		},
+		"data": map[string]interface{}{"ENDPOINT": "https://api.example.com"},
+	})
+}
+
+func TestUpdateAllPasswordsSkipsUnchangedSecrets(t *testing.T) {
+	store := apimock.New()
+	err := store.Set(context.Background(), "database", &apimock.Secret{Buf: []byte("database password")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+	repository := &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+	}
+
+	countPatches := func() int {
+		patches := 0
+		for _, action := range kubernetesClient.Actions() {
+			if action.GetVerb() == "patch" {
+				patches++
+			}
+		}
+		return patches
+	}
+
+	for i, wanted := range []struct {
+		updated   int
+		unchanged int
+		patches   int
+	}{
+		{updated: 1, unchanged: 0, patches: 1},
+		{updated: 0, unchanged: 1, patches: 1},
+	} {
+		response, err := r.UpdateAllPasswords(context.Background(), repository)
+		if err != nil {
+			t.Errorf("UpdateAllPasswords() error = %v", err)
+			return
+		}
+		if len(response.Updated) != wanted.updated || len(response.Unchanged) != wanted.unchanged {
+			t.Errorf("sync %d reported %d updated and %d unchanged secrets, wanted %d and %d", i, len(response.Updated), len(response.Unchanged), wanted.updated, wanted.unchanged)
+		}
+		if countPatches() != wanted.patches {
+			t.Errorf("sync %d resulted in %d patches, wanted %d", i, countPatches(), wanted.patches)
+		}
+	}
+
+	err = store.Set(context.Background(), "database", &apimock.Secret{Buf: []byte("new password")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+	response, err := r.UpdateAllPasswords(context.Background(), repository)
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+	if len(response.Updated) != 1 || countPatches() != 2 {
+		t.Errorf("changed entry was not written")
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	target := secretTarget{
+		name:        types.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+		kind:        targetKindSecret,
+		labels:      map[string]string{"team": "a"},
+		annotations: map[string]string{"owner": "team-a"},
+	}
+	data := map[string]string{"key": "value"}
+	hash := contentHash(target, data)
+
+	tests := []struct {
+		name         string
+		labels       map[string]string
+		annotations  map[string]string
+		existingData map[string]string
+		wanted       bool
+	}{
+		{
+			name:         "same hash, data, labels and annotations",
+			labels:       map[string]string{"team": "a", "foreign": "x"},
+			annotations:  map[string]string{contentHashAnnotation: hash, "owner": "team-a", "foreign": "x"},
+			existingData: map[string]string{"key": "value", "foreign": "x"},
+			wanted:       true,
+		},
+		{
+			name:         "edited data",
+			labels:       map[string]string{"team": "a"},
+			annotations:  map[string]string{contentHashAnnotation: hash, "owner": "team-a"},
+			existingData: map[string]string{"key": "edited"},
+			wanted:       false,
+		},
+		{
+			name:         "missing hash",
+			labels:       map[string]string{"team": "a"},
+			annotations:  map[string]string{"owner": "team-a"},
+			existingData: map[string]string{"key": "value"},
+			wanted:       false,
+		},
+		{
+			name:         "removed label",
+			labels:       nil,
+			annotations:  map[string]string{contentHashAnnotation: hash, "owner": "team-a"},
+			existingData: map[string]string{"key": "value"},
+			wanted:       false,
+		},
+		{
+			name:         "edited annotation",
+			labels:       map[string]string{"team": "a"},
+			annotations:  map[string]string{contentHashAnnotation: hash, "owner": "team-b"},
+			existingData: map[string]string{"key": "value"},
+			wanted:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := metav1.ObjectMeta{Labels: tt.labels, Annotations: tt.annotations}
+			if got := unchanged(existing, tt.existingData, target, hash, data); got != tt.wanted {
+				t.Errorf("unchanged() = %v, wanted %v", got, tt.wanted)
+			}
+		})
+	}
+
+	target.labels = map[string]string{"team": "b"}
+	if contentHash(target, data) == hash {
+		t.Errorf("changed labels do not change the content hash")
+	}
+}
+
+func TestUpdateAllPasswordsRestoresRemovedLabels(t *testing.T) {
+	store := apimock.New()
+	err := store.Set(context.Background(), "database", &apimock.Secret{Buf: []byte("database password")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+	repository := &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+		Labels:        map[string]string{"team": "a"},
+	}
+
+	_, err = r.UpdateAllPasswords(context.Background(), repository)
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+
+	secret, err := kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "someSecret", metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("unable to get secret: %v", err)
+		return
+	}
+	secret.Labels = nil
+	_, err = kubernetesClient.CoreV1().Secrets("testNamespace").Update(context.Background(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		t.Errorf("unable to update secret: %v", err)
+		return
+	}
+
+	response, err := r.UpdateAllPasswords(context.Background(), repository)
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+	if len(response.Updated) != 1 || len(response.Unchanged) != 0 {
+		t.Errorf("sync reported %d updated and %d unchanged secrets, wanted the secret with the removed label to be updated", len(response.Updated), len(response.Unchanged))
+	}
+
+	applied := kubernetesClient.lastApply(t, "secrets", "someSecret")
+	labels := applied.configuration["metadata"].(map[string]interface{})["labels"]
+	if !reflect.DeepEqual(labels, map[string]interface{}{"team": "a", repositoryNameLabel: "someSecret", repositoryNamespaceLabel: "testNamespace"}) {
+		t.Errorf("secret was applied with labels %v, wanted the removed label", labels)
+	}
+}
+
+// handedOverManagedFields returns the managed fields the JSON patch of the resource with the given name replaced.
+func handedOverManagedFields(t *testing.T, kubernetesClient *applyClientset, name string) []metav1.ManagedFieldsEntry {
+	for _, action := range kubernetesClient.Actions() {
+		patchAction, ok := action.(clientgotesting.PatchAction)
+		if !ok || patchAction.GetPatchType() != types.JSONPatchType || patchAction.GetName() != name {
+			continue
+		}
+
+		var operations []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		err := json.Unmarshal(patchAction.GetPatch(), &operations)
+		if err != nil {
+			t.Fatalf("unable to parse patch: %v", err)
+		}
+		for _, operation := range operations {
+			if operation.Op == "replace" && operation.Path == "/metadata/managedFields" {
+				managedFields := make([]metav1.ManagedFieldsEntry, 0)
+				err = json.Unmarshal(operation.Value, &managedFields)
+				if err != nil {
+					t.Fatalf("unable to parse managed fields: %v", err)
+				}
+				return managedFields
+			}
+		}
+	}
+	t.Fatalf("managed fields of '%s' were not handed over", name)
+	return nil
+}
+
+func TestUpdateAllPasswordsHandsOverFieldsWrittenWithUpdate(t *testing.T) {
+	tests := []struct {
+		name            string
+		prunePolicy     string
+		legacyManager   string
+		legacyManagers  []string
+		wanted          map[string]string
+		wantedStaleKeys []string
+	}{
+		{
+			name:            "removed entries are pruned",
+			prunePolicy:     "",
+			legacyManager:   defaultLegacyFieldManager,
+			wanted:          map[string]string{"current": "current password"},
+			wantedStaleKeys: []string{},
+		},
+		{
+			name:            "removed entries are kept and reported as stale",
+			prunePolicy:     prunePolicyKeep,
+			legacyManager:   defaultLegacyFieldManager,
+			wanted:          map[string]string{"current": "current password", "removed": "old password"},
+			wantedStaleKeys: []string{"removed"},
+		},
+		{
+			name:            "fields of a configured legacy manager are handed over",
+			prunePolicy:     "",
+			legacyManager:   "gopass-server",
+			legacyManagers:  []string{defaultLegacyFieldManager, "gopass-server"},
+			wanted:          map[string]string{"current": "current password"},
+			wantedStaleKeys: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := apimock.New()
+			err := store.Set(context.Background(), "current", &apimock.Secret{Buf: []byte("current password")})
+			if err != nil {
+				t.Errorf("unable to set key in store: %v", err)
+				return
+			}
+
+			legacyEntry := managedFieldsEntry(tt.legacyManager, []string{"current", "removed"})
+			legacyEntry.Operation = metav1.ManagedFieldsOperationUpdate
+			foreignEntry := managedFieldsEntry("kubectl-edit", []string{"foreign"})
+			foreignEntry.Operation = metav1.ManagedFieldsOperationUpdate
+
+			kubernetesClient := newApplyClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "testNamespace",
+					Name:            "someSecret",
+					ResourceVersion: "1",
+					ManagedFields:   []metav1.ManagedFieldsEntry{legacyEntry, foreignEntry},
+				},
+				Data: map[string][]byte{
+					"current": []byte("outdated password"),
+					"removed": []byte("old password"),
+					"foreign": []byte("added by someone else"),
+				},
+			})
+			r := &RepositoryServer{
+				Repositories: map[string]*gopassRepo{
+					"testUrl": {store: store},
+				},
+				Client:              &cluster.KubernetesTestClient{},
+				KubernetesClient:    kubernetesClient,
+				LegacyFieldManagers: tt.legacyManagers,
+			}
+
+			response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+				RepositoryURL: "testUrl",
+				SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+				PrunePolicy:   tt.prunePolicy,
+			})
+			if err != nil {
+				t.Errorf("UpdateAllPasswords() error = %v", err)
+				return
+			}
+
+			managedFields := handedOverManagedFields(t, kubernetesClient, "someSecret")
+			managers := make([]string, 0)
+			for _, entry := range managedFields {
+				managers = append(managers, entry.Manager+"/"+string(entry.Operation))
+			}
+			wantedManagers := []string{"kubectl-edit/Update", fieldManager + "/Apply"}
+			if !reflect.DeepEqual(managers, wantedManagers) {
+				t.Errorf("managed fields were handed over to %v, wanted %v", managers, wantedManagers)
+			}
+			if keys := managedKeys(managedFields[1:], []string{fieldManager}); !reflect.DeepEqual(keys, []string{"current", "removed"}) {
+				t.Errorf("keys %v were handed over, wanted the keys written with Update", keys)
+			}
+
+			applied := appliedSecretData(t, kubernetesClient.lastApply(t, "secrets", "someSecret"))
+			if !reflect.DeepEqual(applied, tt.wanted) {
+				t.Errorf("secret was applied with %v, wanted %v", applied, tt.wanted)
+			}
+
+			staleKeys := make([]string, 0)
+			for _, staleKey := range response.StaleKeys {
+				staleKeys = append(staleKeys, staleKey.Key)
+			}
+			if !reflect.DeepEqual(staleKeys, tt.wantedStaleKeys) {
+				t.Errorf("stale keys were %v, wanted %v", staleKeys, tt.wantedStaleKeys)
+			}
+		})
+	}
+}
+
+func TestHandOverPatchWithoutLegacyFields(t *testing.T) {
+	patch, err := handOverPatch(metav1.ObjectMeta{
+		ManagedFields: []metav1.ManagedFieldsEntry{managedFieldsEntry(fieldManager, []string{"key"})},
+	}, []string{defaultLegacyFieldManager})
+	if err != nil || patch != nil {
+		t.Errorf("handOverPatch() = %s, %v, wanted no patch", patch, err)
+	}
+}
+
+func TestHandOverPatchIgnoresUnknownManagers(t *testing.T) {
+	entry := managedFieldsEntry("gopass-server", []string{"key"})
+	entry.Operation = metav1.ManagedFieldsOperationUpdate
+
+	patch, err := handOverPatch(metav1.ObjectMeta{
+		ManagedFields: []metav1.ManagedFieldsEntry{entry},
+	}, []string{defaultLegacyFieldManager})
+	if err != nil || patch != nil {
+		t.Errorf("handOverPatch() = %s, %v, wanted no patch", patch, err)
+	}
+}
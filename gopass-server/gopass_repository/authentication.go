@@ -0,0 +1,152 @@
+package gopass_repository
+
+import (
+	"crypto/x509"
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	ssh_2 "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+// createAuthMethod picks the authentication method matching the transport given by the scheme of the repository URL.
+// For HTTPS repositories the CA bundle is validated, it is passed to the operations along with the auth method. Plain
+// HTTP repositories do not get credentials, as they would be sent unencrypted.
+func createAuthMethod(repositoryUrl string, credentials cluster.Credentials) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(repositoryUrl)
+	if err != nil {
+		log.Printf("unable to parse repository URL '%s': %v", repositoryUrl, err)
+		return nil, err
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		return createSshAuthMethod(credentials)
+	case "https":
+		if len(credentials.CABundle) > 0 {
+			err = validateCABundle(credentials.CABundle)
+			if err != nil {
+				log.Printf("unable to use CA bundle: %v", err)
+				return nil, err
+			}
+		}
+		return createHttpAuthMethod(credentials), nil
+	case "http":
+		if credentials.Username != "" || credentials.Password != "" {
+			return nil, fmt.Errorf("credentials are not sent to '%s' unencrypted, use an HTTPS URL", repositoryUrl)
+		}
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+// createSshAuthMethod uses public key authentication if a private key is given and falls back to password authentication.
+func createSshAuthMethod(credentials cluster.Credentials) (transport.AuthMethod, error) {
+	hostKeyCallback, err := createHostKeyCallback(credentials)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallbackHelper := ssh.HostKeyCallbackHelper{
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	if len(credentials.PrivateKey) > 0 {
+		username := credentials.Username
+		if username == "" {
+			username = ssh.DefaultUsername
+		}
+
+		publicKeys, err := ssh.NewPublicKeys(username, credentials.PrivateKey, credentials.PrivateKeyPassphrase)
+		if err != nil {
+			log.Printf("unable to parse private key: %v", err)
+			return nil, err
+		}
+		publicKeys.HostKeyCallbackHelper = hostKeyCallbackHelper
+
+		return publicKeys, nil
+	}
+
+	return &ssh.Password{
+		User:                  credentials.Username,
+		Password:              credentials.Password,
+		HostKeyCallbackHelper: hostKeyCallbackHelper,
+	}, nil
+}
+
+// createHostKeyCallback verifies host keys against the given known_hosts entries.
+// Host keys are only ignored if this has explicitly been requested.
+func createHostKeyCallback(credentials cluster.Credentials) (ssh_2.HostKeyCallback, error) {
+	if len(credentials.KnownHosts) > 0 {
+		return parseKnownHosts(credentials.KnownHosts)
+	}
+
+	if credentials.InsecureIgnoreHostKey {
+		log.Printf("WARNING: host key verification is disabled")
+		return ssh_2.InsecureIgnoreHostKey(), nil
+	}
+
+	return nil, fmt.Errorf("no known hosts given to verify the host key of the repository")
+}
+
+// parseKnownHosts creates a callback from known_hosts content. The knownhosts package only reads files,
+// so the content is written to a temporary file that is removed right after parsing.
+func parseKnownHosts(knownHosts []byte) (ssh_2.HostKeyCallback, error) {
+	knownHostsFile, err := ioutil.TempFile("", "known_hosts")
+	if err != nil {
+		log.Printf("unable to create file for known hosts: %v", err)
+		return nil, err
+	}
+	defer os.Remove(knownHostsFile.Name())
+
+	_, err = knownHostsFile.Write(knownHosts)
+	closeErr := knownHostsFile.Close()
+	if err != nil {
+		log.Printf("unable to write known hosts: %v", err)
+		return nil, err
+	}
+	if closeErr != nil {
+		log.Printf("unable to write known hosts: %v", closeErr)
+		return nil, closeErr
+	}
+
+	hostKeyCallback, err := knownhosts.New(knownHostsFile.Name())
+	if err != nil {
+		log.Printf("unable to parse known hosts: %v", err)
+		return nil, err
+	}
+
+	return hostKeyCallback, nil
+}
+
+// createHttpAuthMethod uses basic authentication if a username is given. Otherwise the password is sent as a bearer token.
+func createHttpAuthMethod(credentials cluster.Credentials) githttp.AuthMethod {
+	if credentials.Username != "" {
+		return &githttp.BasicAuth{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+
+	if credentials.Password != "" {
+		return &githttp.TokenAuth{
+			Token: credentials.Password,
+		}
+	}
+
+	return nil
+}
+
+// validateCABundle ensures the CA bundle contains certificates. The bundle is passed to go-git with the options of each
+// operation, which trusts it in addition to the system certificates.
+func validateCABundle(caBundle []byte) error {
+	if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+		return fmt.Errorf("no certificates found in CA bundle")
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package gopass_repository
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	ssh_2 "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateAuthMethod(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("unable to generate private key: %v", err)
+		return
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	})
+	hostKey, err := ssh_2.NewSignerFromKey(rsaKey)
+	if err != nil {
+		t.Errorf("unable to create host key: %v", err)
+		return
+	}
+
+	tests := []struct {
+		name          string
+		repositoryUrl string
+		credentials   cluster.Credentials
+		wantErr       bool
+		wantedMethod  string
+		wantedString  string
+	}{
+		{
+			name:          "ssh password authentication without private key",
+			repositoryUrl: "ssh://example.com/password-store",
+			credentials: cluster.Credentials{
+				Username:              "molly.millions",
+				Password:              "my secret",
+				InsecureIgnoreHostKey: true,
+			},
+			wantErr:      false,
+			wantedMethod: ssh.PasswordName,
+			wantedString: fmt.Sprintf("user: %s, name: %s", "molly.millions", ssh.PasswordName),
+		},
+		{
+			name:          "ssh public key authentication with private key",
+			repositoryUrl: "git@example.com:mdreem/password-store.git",
+			credentials: cluster.Credentials{
+				PrivateKey: privateKey,
+				KnownHosts: []byte(knownhosts.Line([]string{"example.com"}, hostKey.PublicKey())),
+			},
+			wantErr:      false,
+			wantedMethod: ssh.PublicKeysName,
+			wantedString: fmt.Sprintf("user: %s, name: %s", ssh.DefaultUsername, ssh.PublicKeysName),
+		},
+		{
+			name:          "invalid ssh private key",
+			repositoryUrl: "ssh://example.com/password-store",
+			credentials: cluster.Credentials{
+				Username:              "git",
+				PrivateKey:            []byte("not a private key"),
+				InsecureIgnoreHostKey: true,
+			},
+			wantErr: true,
+		},
+		{
+			name:          "ssh without known hosts",
+			repositoryUrl: "ssh://example.com/password-store",
+			credentials: cluster.Credentials{
+				PrivateKey: privateKey,
+			},
+			wantErr: true,
+		},
+		{
+			name:          "ssh with invalid known hosts",
+			repositoryUrl: "ssh://example.com/password-store",
+			credentials: cluster.Credentials{
+				PrivateKey: privateKey,
+				KnownHosts: []byte("example.com ssh-rsa not-a-key"),
+			},
+			wantErr: true,
+		},
+		{
+			name:          "https basic authentication with username",
+			repositoryUrl: "https://example.com/password-store.git",
+			credentials: cluster.Credentials{
+				Username: "molly.millions",
+				Password: "my secret",
+			},
+			wantErr:      false,
+			wantedMethod: "http-basic-auth",
+			wantedString: "http-basic-auth - molly.millions:*******",
+		},
+		{
+			name:          "https token authentication without username",
+			repositoryUrl: "https://example.com/password-store.git",
+			credentials: cluster.Credentials{
+				Password: "my token",
+			},
+			wantErr:      false,
+			wantedMethod: "http-token-auth",
+			wantedString: "http-token-auth - *******",
+		},
+		{
+			name:          "http with password is rejected",
+			repositoryUrl: "http://example.com/password-store.git",
+			credentials: cluster.Credentials{
+				Username: "molly.millions",
+				Password: "my secret",
+			},
+			wantErr: true,
+		},
+		{
+			name:          "http with token is rejected",
+			repositoryUrl: "http://example.com/password-store.git",
+			credentials: cluster.Credentials{
+				Password: "my token",
+			},
+			wantErr: true,
+		},
+		{
+			name:          "https with invalid CA bundle",
+			repositoryUrl: "https://example.com/password-store.git",
+			credentials: cluster.Credentials{
+				Password: "my token",
+				CABundle: []byte("not a certificate"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := createAuthMethod(tt.repositoryUrl, tt.credentials)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createAuthMethod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if auth.Name() != tt.wantedMethod {
+				t.Errorf("createAuthMethod() method = %s, wanted %s", auth.Name(), tt.wantedMethod)
+			}
+			if auth.String() != tt.wantedString {
+				t.Errorf("createAuthMethod() = '%s', wanted '%s'", auth.String(), tt.wantedString)
+			}
+		})
+	}
+}
+
+func TestCreateHostKeyCallback(t *testing.T) {
+	knownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("unable to generate host key: %v", err)
+		return
+	}
+	unknownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("unable to generate host key: %v", err)
+		return
+	}
+
+	knownPublicKey, _ := ssh_2.NewPublicKey(&knownKey.PublicKey)
+	unknownPublicKey, _ := ssh_2.NewPublicKey(&unknownKey.PublicKey)
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+
+	tests := []struct {
+		name        string
+		credentials cluster.Credentials
+		hostKey     ssh_2.PublicKey
+		wantErr     bool
+	}{
+		{
+			name: "known host key is accepted",
+			credentials: cluster.Credentials{
+				KnownHosts: []byte(knownhosts.Line([]string{"example.com"}, knownPublicKey)),
+			},
+			hostKey: knownPublicKey,
+			wantErr: false,
+		},
+		{
+			name: "unknown host key is rejected",
+			credentials: cluster.Credentials{
+				KnownHosts: []byte(knownhosts.Line([]string{"example.com"}, knownPublicKey)),
+			},
+			hostKey: unknownPublicKey,
+			wantErr: true,
+		},
+		{
+			name: "known hosts take precedence over insecure mode",
+			credentials: cluster.Credentials{
+				KnownHosts:            []byte(knownhosts.Line([]string{"example.com"}, knownPublicKey)),
+				InsecureIgnoreHostKey: true,
+			},
+			hostKey: unknownPublicKey,
+			wantErr: true,
+		},
+		{
+			name: "any host key is accepted in insecure mode",
+			credentials: cluster.Credentials{
+				InsecureIgnoreHostKey: true,
+			},
+			hostKey: unknownPublicKey,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostKeyCallback, err := createHostKeyCallback(tt.credentials)
+			if err != nil {
+				t.Errorf("createHostKeyCallback() error = %v", err)
+				return
+			}
+
+			err = hostKeyCallback("example.com:22", remote, tt.hostKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("hostKeyCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateAuthMethodForLocalRepository(t *testing.T) {
+	auth, err := createAuthMethod("/tmp/password-store", cluster.Credentials{Username: "molly.millions"})
+	if err != nil {
+		t.Errorf("createAuthMethod() error = %v", err)
+		return
+	}
+	if auth != nil {
+		t.Errorf("createAuthMethod() = %v, wanted no authentication for local repositories", auth)
+	}
+}
+
+func TestCloneTrustsCABundleOfRepository(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+	repositoryUrl := server.URL + "/password-store.git"
+
+	_, err := cloneGopassRepo(repositoryUrl, "", t.TempDir(), cluster.Credentials{Password: "my token", CABundle: caBundle})
+	if err == nil || strings.Contains(err.Error(), "certificate") {
+		t.Errorf("cloneGopassRepo() error = %v, wanted the certificate to be trusted", err)
+	}
+
+	_, err = cloneGopassRepo(repositoryUrl, "", t.TempDir(), cluster.Credentials{Password: "my token"})
+	if err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("cloneGopassRepo() error = %v, wanted the certificate of another repository not to be trusted", err)
+	}
+}
+
+func TestCreateAuthMethodWithCABundleOnly(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+
+	auth, err := createAuthMethod(server.URL+"/password-store.git", cluster.Credentials{CABundle: caBundle})
+	if err != nil || auth != nil {
+		t.Errorf("createAuthMethod() = %v, %v, wanted no authentication", auth, err)
+	}
+
+	auth, err = createAuthMethod("http://example.com/password-store.git", cluster.Credentials{CABundle: caBundle})
+	if err != nil || auth != nil {
+		t.Errorf("createAuthMethod() = %v, %v, wanted no authentication for plain HTTP", auth, err)
+	}
+}
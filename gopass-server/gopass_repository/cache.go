@@ -0,0 +1,158 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"log"
+	"strings"
+)
+
+// entryCache holds the decrypted entries of a repository along with the commits they have been decrypted at.
+// The commits are kept per mount point, the repository itself uses the empty mount point.
+type entryCache struct {
+	commits map[string]string
+	entries map[string]cluster.Secret
+}
+
+func newEntryCache() *entryCache {
+	return &entryCache{
+		commits: make(map[string]string),
+		entries: make(map[string]cluster.Secret),
+	}
+}
+
+// refresh drops the entries below the mount point changed between the cached commit and the given commit. The store
+// path is the directory of the store within the repository. Without a commit to compare to, or if the changes cannot
+// be determined, all entries below the mount point are dropped.
+func (c *entryCache) refresh(mountPoint string, storePath string, repository *git.Repository, commit string) {
+	cachedCommit := c.commits[mountPoint]
+	if commit != "" && cachedCommit == commit {
+		return
+	}
+	c.commits[mountPoint] = commit
+
+	if commit == "" || cachedCommit == "" || repository == nil {
+		c.clear(mountPoint)
+		return
+	}
+
+	changed, err := changedEntries(repository, storePath, cachedCommit, commit)
+	if err != nil {
+		log.Printf("unable to determine changes between '%s' and '%s', dropping cached entries: %v\n", cachedCommit, commit, err)
+		c.clear(mountPoint)
+		return
+	}
+
+	log.Printf("%d entries changed between '%s' and '%s'\n", len(changed), cachedCommit, commit)
+	for _, name := range changed {
+		delete(c.entries, mountedName(mountPoint, name))
+	}
+}
+
+// clear drops all entries below the mount point. Clearing the repository itself drops all entries.
+func (c *entryCache) clear(mountPoint string) {
+	if mountPoint == "" {
+		c.entries = make(map[string]cluster.Secret)
+		return
+	}
+
+	for name := range c.entries {
+		if strings.HasPrefix(name, mountPoint+"/") {
+			delete(c.entries, name)
+		}
+	}
+}
+
+func mountedName(mountPoint string, name string) string {
+	if mountPoint == "" {
+		return name
+	}
+	return mountPoint + "/" + name
+}
+
+// changedEntries returns the names of the entries of the store at storePath added, changed or removed between two commits.
+func changedEntries(repository *git.Repository, storePath string, from string, to string) ([]string, error) {
+	fromTree, err := commitTree(repository, from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := commitTree(repository, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	if storePath != "" {
+		prefix = storePath + "/"
+	}
+
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		for _, path := range []string{change.From.Name, change.To.Name} {
+			if strings.HasPrefix(path, prefix) && strings.HasSuffix(path, ".gpg") {
+				names = append(names, strings.TrimSuffix(strings.TrimPrefix(path, prefix), ".gpg"))
+			}
+		}
+	}
+	return names, nil
+}
+
+func commitTree(repository *git.Repository, hash string) (*object.Tree, error) {
+	commit, err := repository.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	return commit.Tree()
+}
+
+// refreshEntryCache drops the cached entries changed in the repository or any of its mounts.
+func (g *gopassRepo) refreshEntryCache(commit string) error {
+	cache := g.entryCache()
+	cache.refresh("", g.storePath, g.repository, commit)
+
+	for _, mount := range g.mounts {
+		mountCommit, err := headCommit(mount.repository)
+		if err != nil {
+			return err
+		}
+		cache.refresh(mount.mountPoint, "", mount.repository, mountCommit)
+	}
+	return nil
+}
+
+// entryCache returns the cache of decrypted entries of the repository.
+func (g *gopassRepo) entryCache() *entryCache {
+	if g.cache == nil {
+		g.cache = newEntryCache()
+	}
+	return g.cache
+}
+
+// decryptEntry returns the entry from the cache or decrypts it if it is not cached.
+func decryptEntry(ctx context.Context, repo *gopassRepo, name string) (cluster.Secret, error) {
+	cache := repo.entryCache()
+	if entry, ok := cache.entries[name]; ok {
+		return entry, nil
+	}
+
+	secret, err := repo.store.Get(ctx, name, "")
+	if err != nil {
+		return cluster.Secret{}, err
+	}
+
+	entry := cluster.Secret{
+		Name:     name,
+		Password: secret.Password(),
+		Fields:   secretFields(secret),
+	}
+	cache.entries[name] = entry
+	return entry, nil
+}
@@ -0,0 +1,230 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/gopasspw/gopass/pkg/gopass/apimock"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func commitFiles(t *testing.T, repository *git.Repository, directory string, files map[string]string, removed ...string) string {
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatalf("unable to get worktree: %v", err)
+	}
+
+	for name, content := range files {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(directory, name)), 0755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("unable to write to file: %v", err)
+		}
+		_, err = worktree.Add(name)
+		if err != nil {
+			t.Fatalf("unable to add file: %v", err)
+		}
+	}
+	for _, name := range removed {
+		_, err = worktree.Remove(name)
+		if err != nil {
+			t.Fatalf("unable to remove file: %v", err)
+		}
+	}
+
+	hash, err := worktree.Commit("update entries", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("unable to commit: %v", err)
+	}
+	return hash.String()
+}
+
+func TestChangedEntries(t *testing.T) {
+	directory, repository, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+
+	first := commitFiles(t, repository, directory, map[string]string{
+		"team-a/database.gpg": "database",
+		"team-a/token.gpg":    "token",
+		"unchanged.gpg":       "unchanged",
+		".gpg-id":             "someone@example.com",
+	})
+	second := commitFiles(t, repository, directory, map[string]string{
+		"team-a/database.gpg": "new database",
+		"team-b/new.gpg":      "new",
+	}, "team-a/token.gpg")
+
+	changed, err := changedEntries(repository, "", first, second)
+	if err != nil {
+		t.Errorf("changedEntries() error = %v", err)
+		return
+	}
+	sort.Strings(changed)
+
+	wanted := []string{"team-a/database", "team-a/database", "team-a/token", "team-b/new"}
+	if !reflect.DeepEqual(changed, wanted) {
+		t.Errorf("changedEntries() = %v, wanted %v", changed, wanted)
+	}
+
+	changed, err = changedEntries(repository, "team-a", first, second)
+	if err != nil {
+		t.Errorf("changedEntries() error = %v", err)
+		return
+	}
+	sort.Strings(changed)
+
+	wanted = []string{"database", "database", "token"}
+	if !reflect.DeepEqual(changed, wanted) {
+		t.Errorf("changedEntries() of store path 'team-a' = %v, wanted %v", changed, wanted)
+	}
+}
+
+func TestFetchAllPasswordsOnlyDecryptsChangedEntries(t *testing.T) {
+	directory, repository, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+
+	store := &recordingStore{MockAPI: apimock.New()}
+	for _, name := range []string{"database", "token"} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte("password of " + name)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+	repo := &gopassRepo{store: store, repository: repository}
+
+	fetch := func(commit string) {
+		repo.entryCache().refresh("", "", repository, commit)
+		_, err := fetchAllPasswords(context.Background(), repo, func(string) bool { return true })
+		if err != nil {
+			t.Errorf("fetchAllPasswords() error = %v", err)
+		}
+	}
+
+	first := commitFiles(t, repository, directory, map[string]string{"database.gpg": "database", "token.gpg": "token"})
+	fetch(first)
+	fetch(first)
+	if len(store.fetched) != 2 {
+		t.Errorf("decrypted %v, wanted every entry to be decrypted once", store.fetched)
+	}
+
+	store.fetched = nil
+	second := commitFiles(t, repository, directory, map[string]string{"token.gpg": "new token"})
+	fetch(second)
+	if !reflect.DeepEqual(store.fetched, []string{"token"}) {
+		t.Errorf("decrypted %v, wanted only the changed entry 'token'", store.fetched)
+	}
+}
+
+func TestEntryCacheWithoutCommit(t *testing.T) {
+	store := &recordingStore{MockAPI: apimock.New()}
+	err := store.Set(context.Background(), "database", &apimock.Secret{Buf: []byte("password")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+	repo := &gopassRepo{store: store}
+
+	for i := 0; i < 2; i++ {
+		repo.entryCache().refresh("", "", nil, "")
+		_, err := fetchAllPasswords(context.Background(), repo, func(string) bool { return true })
+		if err != nil {
+			t.Errorf("fetchAllPasswords() error = %v", err)
+		}
+	}
+
+	if len(store.fetched) != 2 {
+		t.Errorf("decrypted %v, wanted entries to be decrypted on every sync without a commit", store.fetched)
+	}
+}
+
+func TestEntryCacheClearsOnlyEntriesOfMount(t *testing.T) {
+	cache := newEntryCache()
+	cache.entries["database"] = cluster.Secret{Name: "database"}
+	cache.entries["shared/token"] = cluster.Secret{Name: "shared/token"}
+	cache.entries["shared-database"] = cluster.Secret{Name: "shared-database"}
+
+	cache.refresh("shared", "", nil, "")
+
+	if _, ok := cache.entries["shared/token"]; ok {
+		t.Errorf("entry of mount 'shared' has not been dropped")
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("cache contains %v, wanted only entries outside of mount 'shared'", cache.entries)
+	}
+}
+
+func BenchmarkFetchAllPasswords(b *testing.B) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		b.Skip("gpg not available")
+	}
+
+	repoDir := b.TempDir()
+	unzip(filepath.Join("resources_test", "password-store.zip"), repoDir, b)
+	storeDir := filepath.Join(repoDir, ".password-store")
+
+	homeDirectory, err := createHomeDirectory()
+	if err != nil {
+		b.Fatalf("not able to create home directory: %v", err)
+	}
+	defer removeHomeDirectory(homeDirectory)
+
+	output, err := exec.Command("gpg", "--homedir", gnupgHome(homeDirectory), "--batch", "--import", filepath.Join("resources_test", "gpg-key.pgp")).CombinedOutput()
+	if err != nil {
+		b.Fatalf("not able to import GPG key: %v: %s", err, output)
+	}
+
+	store, err := createNewGopassClient(context.Background(), storeDir, homeDirectory, nil)
+	if err != nil {
+		b.Fatalf("not able to create gopass client: %v", err)
+	}
+	repository, err := git.PlainOpen(storeDir)
+	if err != nil {
+		b.Fatalf("not able to open repository: %v", err)
+	}
+	commit, err := headCommit(repository)
+	if err != nil {
+		b.Fatalf("not able to resolve HEAD: %v", err)
+	}
+
+	wanted := func(string) bool { return true }
+
+	b.Run("without cache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			repo := &gopassRepo{store: store, repository: repository}
+			repo.entryCache().refresh("", "", repository, commit)
+			_, err := fetchAllPasswords(context.Background(), repo, wanted)
+			if err != nil {
+				b.Fatalf("fetchAllPasswords() error = %v", err)
+			}
+		}
+	})
+
+	b.Run("with cache", func(b *testing.B) {
+		repo := &gopassRepo{store: store, repository: repository}
+		for i := 0; i < b.N; i++ {
+			repo.entryCache().refresh("", "", repository, commit)
+			_, err := fetchAllPasswords(context.Background(), repo, wanted)
+			if err != nil {
+				b.Fatalf("fetchAllPasswords() error = %v", err)
+			}
+		}
+	})
+}
@@ -3,13 +3,17 @@ package cluster
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"io/ioutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"syscall"
 )
 
@@ -18,11 +22,27 @@ var execCommandContext = exec.CommandContext
 type Secret struct {
 	Name     string
 	Password string
+	// Fields contains the key-value pairs of the body of the entry
+	Fields map[string]string
+}
+
+// Credentials contain everything needed to authenticate against a git repository.
+type Credentials struct {
+	Username             string
+	Password             string
+	PrivateKey           []byte
+	PrivateKeyPassphrase string
+	CABundle             []byte
+	// KnownHosts contains the known_hosts entries used to verify the host key of SSH repositories
+	KnownHosts []byte
+	// InsecureIgnoreHostKey disables the verification of the host key of SSH repositories
+	InsecureIgnoreHostKey bool
 }
 
 type Client interface {
-	GetRepositoryCredentials(ctx context.Context, authentication *gopass_repository.Authentication) (Secret, error)
-	GetGpgKey(ctx context.Context, namespace string, gpgKeyReference *gopass_repository.GpgKeyReference) error
+	GetRepositoryCredentials(ctx context.Context, authentication *gopass_repository.Authentication) (Credentials, error)
+	GetGpgKey(ctx context.Context, namespace string, gnupgHome string, gpgKeyReference *gopass_repository.GpgKeyReference) error
+	GetResourceVersion(ctx context.Context, namespace string, reference *gopass_repository.ResourceKeyReference) (string, error)
 }
 
 type KubernetesClient struct {
@@ -35,50 +55,164 @@ func New(kubernetesClient kubernetes.Interface) KubernetesClient {
 	}
 }
 
-func (k *KubernetesClient) GetRepositoryCredentials(ctx context.Context, authentication *gopass_repository.Authentication) (Secret, error) {
-	secretMap, err := k.clientset.CoreV1().Secrets((*authentication).Namespace).Get(ctx, authentication.SecretRef, metav1.GetOptions{})
-	if err != nil {
-		log.Printf("unable to fetch Secret: %v", err)
-		return Secret{}, err
+func (k *KubernetesClient) GetRepositoryCredentials(ctx context.Context, authentication *gopass_repository.Authentication) (Credentials, error) {
+	credentials := Credentials{
+		Username:              authentication.Username,
+		InsecureIgnoreHostKey: authentication.InsecureIgnoreHostKey,
 	}
 
-	password, ok := (*secretMap).Data[authentication.SecretKey]
-	if !ok {
-		return Secret{}, fmt.Errorf("unable to find key '%s' in secret '%s' in namespace '%s'", authentication.SecretKey, authentication.SecretRef, authentication.Namespace)
+	if authentication.SecretRef != "" {
+		password, err := k.getSecretValue(ctx, authentication.Namespace, authentication.SecretRef, authentication.SecretKey)
+		if err != nil {
+			return Credentials{}, err
+		}
+		credentials.Password = string(password)
+	}
+
+	if authentication.SshKeyRef != "" {
+		privateKey, err := k.getSecretValue(ctx, authentication.Namespace, authentication.SshKeyRef, authentication.SshKeyRefKey)
+		if err != nil {
+			return Credentials{}, err
+		}
+		credentials.PrivateKey = privateKey
+
+		if authentication.SshKeyPassphraseKey != "" {
+			passphrase, err := k.getSecretValue(ctx, authentication.Namespace, authentication.SshKeyRef, authentication.SshKeyPassphraseKey)
+			if err != nil {
+				return Credentials{}, err
+			}
+			credentials.PrivateKeyPassphrase = string(passphrase)
+		}
+	}
+
+	if authentication.CaBundleRef != nil {
+		caBundle, err := k.getResourceValue(ctx, authentication.Namespace, authentication.CaBundleRef)
+		if err != nil {
+			return Credentials{}, err
+		}
+		credentials.CABundle = caBundle
 	}
 
-	return Secret{
-		Name:     authentication.Username,
-		Password: string(password),
-	}, nil
+	if authentication.KnownHostsRef != nil {
+		knownHosts, err := k.getResourceValue(ctx, authentication.Namespace, authentication.KnownHostsRef)
+		if err != nil {
+			return Credentials{}, err
+		}
+		credentials.KnownHosts = knownHosts
+	}
+
+	return credentials, nil
 }
 
-func (k *KubernetesClient) GetGpgKey(ctx context.Context, namespace string, gpgKeyReference *gopass_repository.GpgKeyReference) error {
+// GetGpgKey imports the referenced GPG key into the keyring inside gnupgHome.
+func (k *KubernetesClient) GetGpgKey(ctx context.Context, namespace string, gnupgHome string, gpgKeyReference *gopass_repository.GpgKeyReference) error {
 	log.Printf("add gpg key")
 
-	secretMap, err := k.clientset.CoreV1().Secrets(namespace).Get(ctx, gpgKeyReference.GpgKeyRef, metav1.GetOptions{})
+	gpgKey, err := k.getSecretValue(ctx, namespace, gpgKeyReference.GpgKeyRef, gpgKeyReference.GpgKeyRefKey)
 	if err != nil {
-		log.Printf("unable to fetch Secret: %v", err)
 		return err
 	}
 
-	gpgKey, ok := (*secretMap).Data[gpgKeyReference.GpgKeyRefKey]
-	if !ok {
-		return fmt.Errorf("unable to find key '%s' in secret '%s' in namespace '%s'", gpgKeyReference.GpgKeyRefKey, gpgKeyReference.GpgKeyRef, namespace)
+	var passphrase []byte
+	if gpgKeyReference.GpgPassphraseRef != "" {
+		passphrase, err = k.getSecretValue(ctx, namespace, gpgKeyReference.GpgPassphraseRef, gpgKeyReference.GpgPassphraseRefKey)
+		if err != nil {
+			return err
+		}
+
+		// has to be configured before the agent is started by importing the key
+		err = allowPresetPassphrase(gnupgHome)
+		if err != nil {
+			log.Printf("unable to configure gpg-agent: %v", err)
+			return err
+		}
 	}
 
-	_, err = addKey(ctx, gpgKey)
+	_, err = addKey(ctx, gnupgHome, gpgKey)
 	if err != nil {
 		log.Printf("unable to add key: %v", err)
 		return err
 	}
 
+	if passphrase != nil {
+		err = presetPassphrase(ctx, gnupgHome, passphrase)
+		if err != nil {
+			log.Printf("unable to preset passphrase: %v", err)
+			return err
+		}
+	}
+
 	return nil
 }
 
-func addKey(ctx context.Context, key []byte) ([]byte, error) {
+// GetResourceVersion returns the resourceVersion of the referenced Secret or ConfigMap. Without a kind a Secret is assumed.
+func (k *KubernetesClient) GetResourceVersion(ctx context.Context, namespace string, reference *gopass_repository.ResourceKeyReference) (string, error) {
+	switch reference.Kind {
+	case "", "Secret":
+		secret, err := k.clientset.CoreV1().Secrets(namespace).Get(ctx, reference.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return secret.ResourceVersion, nil
+	case "ConfigMap":
+		configMap, err := k.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, reference.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return configMap.ResourceVersion, nil
+	default:
+		return "", fmt.Errorf("unsupported kind '%s' referenced by '%s' in namespace '%s'", reference.Kind, reference.Name, namespace)
+	}
+}
+
+// getResourceValue fetches the value of a key inside a Secret or a ConfigMap. Without a kind a Secret is assumed.
+func (k *KubernetesClient) getResourceValue(ctx context.Context, namespace string, reference *gopass_repository.ResourceKeyReference) ([]byte, error) {
+	switch reference.Kind {
+	case "", "Secret":
+		return k.getSecretValue(ctx, namespace, reference.Name, reference.Key)
+	case "ConfigMap":
+		return k.getConfigMapValue(ctx, namespace, reference.Name, reference.Key)
+	default:
+		return nil, fmt.Errorf("unsupported kind '%s' referenced by '%s' in namespace '%s'", reference.Kind, reference.Name, namespace)
+	}
+}
+
+func (k *KubernetesClient) getConfigMapValue(ctx context.Context, namespace string, name string, key string) ([]byte, error) {
+	configMap, err := k.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("unable to fetch ConfigMap: %v", err)
+		return nil, err
+	}
+
+	if value, ok := (*configMap).Data[key]; ok {
+		return []byte(value), nil
+	}
+
+	if value, ok := (*configMap).BinaryData[key]; ok {
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unable to find key '%s' in configmap '%s' in namespace '%s'", key, name, namespace)
+}
+
+func (k *KubernetesClient) getSecretValue(ctx context.Context, namespace string, name string, key string) ([]byte, error) {
+	secretMap, err := k.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("unable to fetch Secret: %v", err)
+		return nil, err
+	}
+
+	value, ok := (*secretMap).Data[key]
+	if !ok {
+		return nil, fmt.Errorf("unable to find key '%s' in secret '%s' in namespace '%s'", key, name, namespace)
+	}
+
+	return value, nil
+}
+
+func addKey(ctx context.Context, gnupgHome string, key []byte) ([]byte, error) {
 	args := make([]string, 0)
-	args = append(args, "--import")
+	args = append(args, "--homedir", gnupgHome, "--batch", "--import")
 	cmd := execCommandContext(ctx, "gpg", args...)
 	cmd.Stdin = bytes.NewReader(key)
 	cmd.Stderr = os.Stderr
@@ -86,3 +220,66 @@ func addKey(ctx context.Context, key []byte) ([]byte, error) {
 
 	return cmd.Output()
 }
+
+func allowPresetPassphrase(gnupgHome string) error {
+	return ioutil.WriteFile(filepath.Join(gnupgHome, "gpg-agent.conf"), []byte("allow-preset-passphrase\n"), 0600)
+}
+
+// presetPassphrase caches the passphrase for all secret keys in gpg-agent, so that decryption works non-interactively.
+func presetPassphrase(ctx context.Context, gnupgHome string, passphrase []byte) error {
+	keygrips, err := listKeygrips(ctx, gnupgHome)
+	if err != nil {
+		return err
+	}
+
+	if len(keygrips) == 0 {
+		return fmt.Errorf("no secret keys found to preset the passphrase for")
+	}
+
+	var commands bytes.Buffer
+	for _, keygrip := range keygrips {
+		// a timeout of -1 keeps the passphrase until the agent terminates
+		commands.WriteString(fmt.Sprintf("PRESET_PASSPHRASE %s -1 %s\n", keygrip, strings.ToUpper(hex.EncodeToString(passphrase))))
+	}
+	commands.WriteString("/bye\n")
+
+	cmd := execCommandContext(ctx, "gpg-connect-agent", "--homedir", gnupgHome)
+	cmd.Stdin = &commands
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "ERR") {
+			return fmt.Errorf("gpg-agent refused passphrase: %s", line)
+		}
+	}
+
+	return nil
+}
+
+// listKeygrips returns the keygrips of all secret keys and subkeys, which identify the keys inside gpg-agent.
+func listKeygrips(ctx context.Context, gnupgHome string) ([]string, error) {
+	cmd := execCommandContext(ctx, "gpg", "--homedir", gnupgHome, "--batch", "--with-colons", "--with-keygrip", "--list-secret-keys")
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	keygrips := make([]string, 0)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) > 9 && fields[0] == "grp" && fields[9] != "" {
+			keygrips = append(keygrips, fields[9])
+		}
+	}
+
+	return keygrips, nil
+}
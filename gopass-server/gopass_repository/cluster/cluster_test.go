@@ -3,12 +3,15 @@ package cluster
 import (
 	"context"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"io/ioutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"os/exec"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -24,7 +27,7 @@ func TestKubernetesClient_GetRepositoryCredentials(t *testing.T) {
 		name            string
 		fields          fields
 		args            args
-		want            Secret
+		want            Credentials
 		wantErr         bool
 		wantedErrorText string
 	}{
@@ -52,8 +55,8 @@ func TestKubernetesClient_GetRepositoryCredentials(t *testing.T) {
 					SecretKey: "someKey",
 				},
 			},
-			want: Secret{
-				Name:     "molly.millions",
+			want: Credentials{
+				Username: "molly.millions",
 				Password: "my secret",
 			},
 			wantErr:         false,
@@ -83,7 +86,7 @@ func TestKubernetesClient_GetRepositoryCredentials(t *testing.T) {
 					SecretKey: "someKey",
 				},
 			},
-			want:            Secret{},
+			want:            Credentials{},
 			wantErr:         true,
 			wantedErrorText: "secrets \"wrongRef\" not found",
 		},
@@ -111,10 +114,181 @@ func TestKubernetesClient_GetRepositoryCredentials(t *testing.T) {
 					SecretKey: "wrongKey",
 				},
 			},
-			want:            Secret{},
+			want:            Credentials{},
 			wantErr:         true,
 			wantedErrorText: "unable to find key 'wrongKey' in secret 'someRef' in namespace 'testNameSpace'",
 		},
+		{
+			name: "successfully fetched ssh key with passphrase",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "sshKeyRef",
+							Namespace: "testNameSpace",
+						},
+						Data: map[string][]byte{
+							"id_ed25519": []byte("my private key"),
+							"passphrase": []byte("my passphrase"),
+						},
+					},
+				),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace:           "testNameSpace",
+					Username:            "git",
+					SshKeyRef:           "sshKeyRef",
+					SshKeyRefKey:        "id_ed25519",
+					SshKeyPassphraseKey: "passphrase",
+				},
+			},
+			want: Credentials{
+				Username:             "git",
+				PrivateKey:           []byte("my private key"),
+				PrivateKeyPassphrase: "my passphrase",
+			},
+			wantErr:         false,
+			wantedErrorText: "",
+		},
+		{
+			name: "unable to find passphrase of ssh key",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "sshKeyRef",
+							Namespace: "testNameSpace",
+						},
+						Data: map[string][]byte{
+							"id_ed25519": []byte("my private key"),
+						},
+					},
+				),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace:           "testNameSpace",
+					Username:            "git",
+					SshKeyRef:           "sshKeyRef",
+					SshKeyRefKey:        "id_ed25519",
+					SshKeyPassphraseKey: "passphrase",
+				},
+			},
+			want:            Credentials{},
+			wantErr:         true,
+			wantedErrorText: "unable to find key 'passphrase' in secret 'sshKeyRef' in namespace 'testNameSpace'",
+		},
+		{
+			name: "successfully fetched CA bundle from ConfigMap",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "ca-bundle",
+							Namespace: "testNameSpace",
+						},
+						Data: map[string]string{
+							"ca.crt": "my ca bundle",
+						},
+					},
+				),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace: "testNameSpace",
+					Username:  "molly.millions",
+					CaBundleRef: &gopass_repository.ResourceKeyReference{
+						Kind: "ConfigMap",
+						Name: "ca-bundle",
+						Key:  "ca.crt",
+					},
+				},
+			},
+			want: Credentials{
+				Username: "molly.millions",
+				CABundle: []byte("my ca bundle"),
+			},
+			wantErr:         false,
+			wantedErrorText: "",
+		},
+		{
+			name: "successfully fetched known hosts from Secret",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "known-hosts",
+							Namespace: "testNameSpace",
+						},
+						Data: map[string][]byte{
+							"known_hosts": []byte("example.com ssh-ed25519 AAAA"),
+						},
+					},
+				),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace: "testNameSpace",
+					Username:  "git",
+					KnownHostsRef: &gopass_repository.ResourceKeyReference{
+						Name: "known-hosts",
+						Key:  "known_hosts",
+					},
+				},
+			},
+			want: Credentials{
+				Username:   "git",
+				KnownHosts: []byte("example.com ssh-ed25519 AAAA"),
+			},
+			wantErr:         false,
+			wantedErrorText: "",
+		},
+		{
+			name: "insecure host key verification is passed on",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace:             "testNameSpace",
+					Username:              "git",
+					InsecureIgnoreHostKey: true,
+				},
+			},
+			want: Credentials{
+				Username:              "git",
+				InsecureIgnoreHostKey: true,
+			},
+			wantErr:         false,
+			wantedErrorText: "",
+		},
+		{
+			name: "unsupported kind of CA bundle reference",
+			fields: fields{
+				clientset: fake.NewSimpleClientset(),
+			},
+			args: args{
+				ctx: nil,
+				authentication: &gopass_repository.Authentication{
+					Namespace: "testNameSpace",
+					Username:  "molly.millions",
+					CaBundleRef: &gopass_repository.ResourceKeyReference{
+						Kind: "Pod",
+						Name: "ca-bundle",
+						Key:  "ca.crt",
+					},
+				},
+			},
+			want:            Credentials{},
+			wantErr:         true,
+			wantedErrorText: "unsupported kind 'Pod' referenced by 'ca-bundle' in namespace 'testNameSpace'",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -276,7 +450,7 @@ func TestKubernetesClient_GetGpgKey(t *testing.T) {
 				execCommandContext = originalExecCommandContext
 			}()
 
-			err := k.GetGpgKey(tt.args.ctx, "testNameSpace", tt.args.gpgKeyReference)
+			err := k.GetGpgKey(tt.args.ctx, "testNameSpace", "/tmp/gnupg", tt.args.gpgKeyReference)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetGpgKey() error = %v, wantErr %v", err, tt.wantErr)
@@ -290,6 +464,150 @@ func TestKubernetesClient_GetGpgKey(t *testing.T) {
 	}
 }
 
+func TestAddKeyUsesGnupgHome(t *testing.T) {
+	var calledArgs []string
+
+	originalExecCommandContext := execCommandContext
+	execCommandContext = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calledArgs = args
+		return mockExecCommandContext(ctx, name, args...)
+	}
+	defer func() {
+		execCommandContext = originalExecCommandContext
+	}()
+
+	_, err := addKey(context.Background(), "/tmp/gnupg", []byte("my key"))
+	if err != nil {
+		t.Errorf("addKey() error = %v", err)
+		return
+	}
+
+	wantedArgs := []string{"--homedir", "/tmp/gnupg", "--batch", "--import"}
+	if !reflect.DeepEqual(calledArgs, wantedArgs) {
+		t.Errorf("addKey() called gpg with %v, wanted %v", calledArgs, wantedArgs)
+	}
+}
+
+func TestKubernetesClient_GetGpgKeyWithPassphrase(t *testing.T) {
+	gnupgHome := t.TempDir()
+
+	originalExecCommandContext := execCommandContext
+	execCommandContext = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		switch {
+		case name == "gpg-connect-agent":
+			return exec.CommandContext(ctx, "echo", "OK")
+		case len(args) > 0 && args[len(args)-1] == "--list-secret-keys":
+			return exec.CommandContext(ctx, "echo", "sec:u:3072:1:848BD3809F2B3C3B:1792226400:::u:::scESC:::+:::23::0:\ngrp:::::::::0C4BD1FD01A17D9F9B10F6DB577762159FD8E8B8:")
+		default:
+			return mockExecCommandContext(ctx, name, args...)
+		}
+	}
+	defer func() {
+		execCommandContext = originalExecCommandContext
+	}()
+
+	k := &KubernetesClient{
+		clientset: fake.NewSimpleClientset(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gpg-key",
+					Namespace: "testNameSpace",
+				},
+				Data: map[string][]byte{
+					"gpg-key-ref": []byte("my secret"),
+					"passphrase":  []byte("my passphrase"),
+				},
+			},
+		),
+	}
+
+	err := k.GetGpgKey(context.Background(), "testNameSpace", gnupgHome, &gopass_repository.GpgKeyReference{
+		GpgKeyRef:           "gpg-key",
+		GpgKeyRefKey:        "gpg-key-ref",
+		GpgPassphraseRef:    "gpg-key",
+		GpgPassphraseRefKey: "passphrase",
+	})
+	if err != nil {
+		t.Errorf("GetGpgKey() error = %v", err)
+		return
+	}
+
+	agentConfig, err := ioutil.ReadFile(filepath.Join(gnupgHome, "gpg-agent.conf"))
+	if err != nil {
+		t.Errorf("unable to read gpg-agent.conf: %v", err)
+		return
+	}
+	if !strings.Contains(string(agentConfig), "allow-preset-passphrase") {
+		t.Errorf("gpg-agent.conf does not allow presetting passphrases: %s", agentConfig)
+	}
+
+	err = k.GetGpgKey(context.Background(), "testNameSpace", gnupgHome, &gopass_repository.GpgKeyReference{
+		GpgKeyRef:           "gpg-key",
+		GpgKeyRefKey:        "gpg-key-ref",
+		GpgPassphraseRef:    "gpg-key",
+		GpgPassphraseRefKey: "unknown",
+	})
+	wantedErrorText := "unable to find key 'unknown' in secret 'gpg-key' in namespace 'testNameSpace'"
+	if err == nil || err.Error() != wantedErrorText {
+		t.Errorf("GetGpgKey() error = '%v', wantedErrorText '%v'", err, wantedErrorText)
+	}
+}
+
+func TestPresetPassphrase(t *testing.T) {
+	tests := []struct {
+		name            string
+		keyListing      string
+		agentResponse   string
+		wantErr         bool
+		wantedErrorText string
+	}{
+		{
+			name:          "passphrase is preset for all keygrips",
+			keyListing:    "grp:::::::::0C4BD1FD01A17D9F9B10F6DB577762159FD8E8B8:\ngrp:::::::::4B2EE014A318C122C44FA4FA3A1639CAF74B7D5C:",
+			agentResponse: "OK\nOK",
+			wantErr:       false,
+		},
+		{
+			name:            "no secret keys imported",
+			keyListing:      "",
+			agentResponse:   "OK",
+			wantErr:         true,
+			wantedErrorText: "no secret keys found to preset the passphrase for",
+		},
+		{
+			name:            "agent refuses passphrase",
+			keyListing:      "grp:::::::::0C4BD1FD01A17D9F9B10F6DB577762159FD8E8B8:",
+			agentResponse:   "ERR 67108903 Not supported <GPG Agent>",
+			wantErr:         true,
+			wantedErrorText: "gpg-agent refused passphrase: ERR 67108903 Not supported <GPG Agent>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			originalExecCommandContext := execCommandContext
+			execCommandContext = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+				if name == "gpg-connect-agent" {
+					return exec.CommandContext(ctx, "echo", tt.agentResponse)
+				}
+				return exec.CommandContext(ctx, "echo", tt.keyListing)
+			}
+			defer func() {
+				execCommandContext = originalExecCommandContext
+			}()
+
+			err := presetPassphrase(context.Background(), "/tmp/gnupg", []byte("my passphrase"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("presetPassphrase() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && (tt.wantedErrorText != err.Error()) {
+				t.Errorf("presetPassphrase() error = '%v', wantedErrorText '%v'", err, tt.wantedErrorText)
+			}
+		})
+	}
+}
+
 func mockExecCommandContext(ctx context.Context, _ string, args ...string) *exec.Cmd {
 	return exec.CommandContext(ctx, "echo", args...)
 }
@@ -297,3 +615,35 @@ func mockExecCommandContext(ctx context.Context, _ string, args ...string) *exec
 func mockFailedCommandContext(ctx context.Context, _ string, _ ...string) *exec.Cmd {
 	return exec.CommandContext(ctx, "thisCommandDoesNotExist")
 }
+
+func TestKubernetesClient_GetResourceVersion(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "gpg-key", Namespace: "someNamespace", ResourceVersion: "42"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "known-hosts", Namespace: "someNamespace", ResourceVersion: "7"}},
+	)
+
+	tests := []struct {
+		name      string
+		reference *gopass_repository.ResourceKeyReference
+		want      string
+		wantErr   bool
+	}{
+		{name: "secret without kind", reference: &gopass_repository.ResourceKeyReference{Name: "gpg-key"}, want: "42"},
+		{name: "config map", reference: &gopass_repository.ResourceKeyReference{Kind: "ConfigMap", Name: "known-hosts"}, want: "7"},
+		{name: "missing secret", reference: &gopass_repository.ResourceKeyReference{Kind: "Secret", Name: "missing"}, wantErr: true},
+		{name: "unsupported kind", reference: &gopass_repository.ResourceKeyReference{Kind: "Pod", Name: "gpg-key"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := New(clientset)
+			got, err := k.GetResourceVersion(context.Background(), "someNamespace", tt.reference)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetResourceVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetResourceVersion() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
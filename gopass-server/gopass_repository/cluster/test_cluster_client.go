@@ -8,12 +8,18 @@ import (
 )
 
 type KubernetesTestClient struct {
+	// ResourceVersions contains the resourceVersions of referenced Secrets and ConfigMaps by name
+	ResourceVersions map[string]string
 }
 
-func (*KubernetesTestClient) GetRepositoryCredentials(_ context.Context, _ *gopass_repository.Authentication) (Secret, error) {
-	return Secret{}, nil
+func (*KubernetesTestClient) GetRepositoryCredentials(_ context.Context, _ *gopass_repository.Authentication) (Credentials, error) {
+	return Credentials{}, nil
 }
 
-func (*KubernetesTestClient) GetGpgKey(_ context.Context, _ string, _ *gopass_repository.GpgKeyReference) error {
+func (*KubernetesTestClient) GetGpgKey(_ context.Context, _ string, _ string, _ *gopass_repository.GpgKeyReference) error {
 	return nil
 }
+
+func (k *KubernetesTestClient) GetResourceVersion(_ context.Context, _ string, reference *gopass_repository.ResourceKeyReference) (string, error) {
+	return k.ResourceVersions[reference.Name], nil
+}
@@ -0,0 +1,72 @@
+package gopass_repository
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"log"
+)
+
+// updateConfigMap writes the ConfigMap unless it already contains the data. It reports whether it was written.
+func (r *RepositoryServer) updateConfigMap(ctx context.Context, target secretTarget, data map[string]string) (bool, error) {
+	hash := contentHash(target, data)
+
+	existingConfigMap, err := getConfigMap(ctx, r.KubernetesClient, target.name)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Printf("unable to fetch config map: %v\n", err)
+		return false, err
+	}
+
+	if err == nil && unchanged(existingConfigMap.ObjectMeta, existingConfigMap.Data, target, hash, data) {
+		log.Printf("config map '%s' is unchanged\n", target.name)
+		return false, nil
+	}
+
+	log.Printf("updating config map '%s'\n", target.name)
+	if err == nil {
+		err = r.handOverConfigMap(ctx, existingConfigMap)
+		if err != nil {
+			log.Printf("unable to hand over fields of config map: %v\n", err)
+			return false, err
+		}
+	}
+
+	err = r.applyConfigMap(ctx, target, data, hash)
+	if err != nil {
+		log.Printf("not able to apply config map: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func getConfigMap(ctx context.Context, clientset kubernetes.Interface, namespacedName types.NamespacedName) (*corev1.ConfigMap, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespacedName.Namespace).Get(ctx, namespacedName.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+func (r *RepositoryServer) deleteConfigMap(ctx context.Context, namespacedName types.NamespacedName) (bool, error) {
+	log.Printf("deleting config map")
+
+	configMap, err := getConfigMap(ctx, r.KubernetesClient, namespacedName)
+
+	if configMap == nil {
+		log.Printf("config map not found")
+		return true, nil
+	}
+
+	err = r.KubernetesClient.CoreV1().ConfigMaps(namespacedName.Namespace).Delete(ctx, namespacedName.Name, metav1.DeleteOptions{})
+	if err != nil {
+		log.Printf("unable to delete config map: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
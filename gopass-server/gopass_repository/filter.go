@@ -0,0 +1,93 @@
+package gopass_repository
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// entryFilter decides which entries of a repository are synced, based on glob patterns matched against their names.
+// Besides the patterns supported by path.Match, "**" matches any number of path segments.
+type entryFilter struct {
+	include []string
+	exclude []string
+}
+
+// newEntryFilter creates a filter that accepts entries matching any include pattern and no exclude pattern.
+// Without include patterns all entries are accepted.
+func newEntryFilter(include []string, exclude []string) (entryFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		err := validatePattern(pattern)
+		if err != nil {
+			return entryFilter{}, err
+		}
+	}
+
+	return entryFilter{
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+func (f entryFilter) matches(name string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "**" {
+			continue
+		}
+		_, err := path.Match(segment, "")
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchGlob(pattern string, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(patternSegments []string, nameSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return len(nameSegments) == 0
+	}
+
+	if patternSegments[0] == "**" {
+		for i := 0; i <= len(nameSegments); i++ {
+			if matchSegments(patternSegments[1:], nameSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(nameSegments) == 0 {
+		return false
+	}
+
+	matched, err := path.Match(patternSegments[0], nameSegments[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(patternSegments[1:], nameSegments[1:])
+}
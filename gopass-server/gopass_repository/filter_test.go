@@ -0,0 +1,106 @@
+package gopass_repository
+
+import (
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "team-a/prod/**", name: "team-a/prod/database", want: true},
+		{pattern: "team-a/prod/**", name: "team-a/prod/database/password", want: true},
+		{pattern: "team-a/prod/**", name: "team-a/prod", want: true},
+		{pattern: "team-a/prod/**", name: "team-a/staging/database", want: false},
+		{pattern: "team-a/prod/**", name: "team-b/prod/database", want: false},
+		{pattern: "team-a/*", name: "team-a/database", want: true},
+		{pattern: "team-a/*", name: "team-a/prod/database", want: false},
+		{pattern: "**/database", name: "team-a/prod/database", want: true},
+		{pattern: "**/database", name: "database", want: true},
+		{pattern: "**/database", name: "team-a/prod/database-password", want: false},
+		{pattern: "team-*/**/token", name: "team-b/ci/github/token", want: true},
+		{pattern: "team-a/db-?", name: "team-a/db-1", want: true},
+		{pattern: "**", name: "anything/at/all", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchGlob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		entries map[string]bool
+	}{
+		{
+			name: "everything is accepted without patterns",
+			entries: map[string]bool{
+				"team-a/prod/database": true,
+				"team-b/token":         true,
+			},
+		},
+		{
+			name:    "only included entries are accepted",
+			include: []string{"team-a/prod/**", "shared/*"},
+			entries: map[string]bool{
+				"team-a/prod/database":   true,
+				"shared/registry":        true,
+				"shared/nested/registry": false,
+				"team-b/token":           false,
+			},
+		},
+		{
+			name:    "excluded entries are rejected even if included",
+			include: []string{"team-a/**"},
+			exclude: []string{"**/personal/**"},
+			entries: map[string]bool{
+				"team-a/prod/database":      true,
+				"team-a/personal/bank":      false,
+				"team-a/prod/personal/bank": false,
+			},
+		},
+		{
+			name:    "exclude without include",
+			exclude: []string{"archive/**"},
+			entries: map[string]bool{
+				"team-a/prod/database": true,
+				"archive/old-token":    false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newEntryFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Errorf("newEntryFilter() error = %v", err)
+				return
+			}
+
+			for entry, want := range tt.entries {
+				if got := filter.matches(entry); got != want {
+					t.Errorf("matches(%s) = %v, want %v", entry, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewEntryFilterWithInvalidPattern(t *testing.T) {
+	_, err := newEntryFilter([]string{"team-a/["}, nil)
+	if err == nil {
+		t.Errorf("newEntryFilter() expected error for invalid pattern")
+	}
+
+	_, err = newEntryFilter(nil, []string{""})
+	if err == nil {
+		t.Errorf("newEntryFilter() expected error for empty pattern")
+	}
+}
@@ -0,0 +1,93 @@
+package gopass_repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/protobuf/proto"
+	"log"
+	"sort"
+)
+
+// initializationFingerprint identifies the parameters a repository has been initialized with. Besides the parameters
+// themselves it covers the resourceVersions of the referenced Secrets and ConfigMaps, so rotating credentials or the
+// GPG key changes the fingerprint as well.
+func (r *RepositoryServer) initializationFingerprint(ctx context.Context, repositoryInitialization *gopass_repository.RepositoryInitialization) (string, error) {
+	repository := repositoryInitialization.Repository
+	parameters := &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			RepositoryURL:  repository.RepositoryURL,
+			Ref:            repository.Ref,
+			StorePath:      repository.StorePath,
+			Authentication: repository.Authentication,
+			Mounts:         repository.Mounts,
+		},
+		GpgKeyReference: repositoryInitialization.GpgKeyReference,
+	}
+
+	marshalledParameters, err := proto.MarshalOptions{Deterministic: true}.Marshal(parameters)
+	if err != nil {
+		log.Printf("unable to marshal initialization parameters: %v", err)
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(marshalledParameters)
+
+	namespace := repository.GetAuthentication().GetNamespace()
+	for _, reference := range referencedResources(repositoryInitialization) {
+		resourceVersion, err := r.Client.GetResourceVersion(ctx, namespace, reference)
+		if err != nil {
+			log.Printf("unable to fetch resourceVersion of %s '%s': %v", reference.Kind, reference.Name, err)
+			return "", err
+		}
+		_, _ = fmt.Fprintf(hash, "%s/%s=%s\n", reference.Kind, reference.Name, resourceVersion)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// referencedResources returns the Secrets and ConfigMaps referenced by the initialization parameters, sorted by kind and name.
+func referencedResources(repositoryInitialization *gopass_repository.RepositoryInitialization) []*gopass_repository.ResourceKeyReference {
+	references := make(map[string]*gopass_repository.ResourceKeyReference)
+	add := func(kind string, name string) {
+		if name == "" {
+			return
+		}
+		if kind == "" {
+			kind = "Secret"
+		}
+		references[kind+"/"+name] = &gopass_repository.ResourceKeyReference{Kind: kind, Name: name}
+	}
+	addAuthentication := func(authentication *gopass_repository.Authentication) {
+		add("Secret", authentication.GetSecretRef())
+		add("Secret", authentication.GetSshKeyRef())
+		if authentication.GetCaBundleRef() != nil {
+			add(authentication.GetCaBundleRef().Kind, authentication.GetCaBundleRef().Name)
+		}
+		if authentication.GetKnownHostsRef() != nil {
+			add(authentication.GetKnownHostsRef().Kind, authentication.GetKnownHostsRef().Name)
+		}
+	}
+
+	addAuthentication(repositoryInitialization.GetRepository().GetAuthentication())
+	for _, mount := range repositoryInitialization.GetRepository().GetMounts() {
+		addAuthentication(mount.GetAuthentication())
+	}
+	add("Secret", repositoryInitialization.GetGpgKeyReference().GetGpgKeyRef())
+	add("Secret", repositoryInitialization.GetGpgKeyReference().GetGpgPassphraseRef())
+
+	keys := make([]string, 0, len(references))
+	for key := range references {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sortedReferences := make([]*gopass_repository.ResourceKeyReference, 0, len(keys))
+	for _, key := range keys {
+		sortedReferences = append(sortedReferences, references[key])
+	}
+	return sortedReferences
+}
@@ -0,0 +1,140 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReferencedResources(t *testing.T) {
+	repositoryInitialization := &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			Authentication: &gopass_repository.Authentication{
+				SecretRef:     "git-credentials",
+				KnownHostsRef: &gopass_repository.ResourceKeyReference{Kind: "ConfigMap", Name: "known-hosts", Key: "known_hosts"},
+			},
+			Mounts: []*gopass_repository.Mount{
+				{Authentication: &gopass_repository.Authentication{SshKeyRef: "deploy-key", SecretRef: "git-credentials"}},
+			},
+		},
+		GpgKeyReference: &gopass_repository.GpgKeyReference{
+			GpgKeyRef:        "gpg-key",
+			GpgPassphraseRef: "gpg-key",
+		},
+	}
+
+	wanted := []*gopass_repository.ResourceKeyReference{
+		{Kind: "ConfigMap", Name: "known-hosts"},
+		{Kind: "Secret", Name: "deploy-key"},
+		{Kind: "Secret", Name: "git-credentials"},
+		{Kind: "Secret", Name: "gpg-key"},
+	}
+
+	got := referencedResources(repositoryInitialization)
+	if len(got) != len(wanted) {
+		t.Errorf("referencedResources() = %v, wanted %v", got, wanted)
+		return
+	}
+	for i := range wanted {
+		if !proto.Equal(got[i], wanted[i]) {
+			t.Errorf("referencedResources()[%d] = %v, wanted %v", i, got[i], wanted[i])
+		}
+	}
+}
+
+func TestInitializeRepositoryAgainWhenParametersChange(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+
+	client := &cluster.KubernetesTestClient{ResourceVersions: map[string]string{"gpg-key": "1"}}
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       client,
+	}
+
+	repositoryInitialization := &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			RepositoryURL:  repoDir,
+			Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		},
+		GpgKeyReference: &gopass_repository.GpgKeyReference{GpgKeyRef: "gpg-key", GpgKeyRefKey: "private.key"},
+	}
+
+	initialize := func() *gopassRepo {
+		response, err := r.InitializeRepository(context.Background(), repositoryInitialization)
+		if err != nil || !response.Successful {
+			t.Fatalf("not able to initialize repository: %v, %s", err, response.GetErrorMessage())
+		}
+		return r.Repositories[repoDir]
+	}
+
+	first := initialize()
+	defer func() { r.Repositories[repoDir].remove() }()
+
+	if initialize() != first {
+		t.Errorf("repository has been initialized again without changed parameters")
+	}
+
+	client.ResourceVersions["gpg-key"] = "2"
+	rotated := initialize()
+	if rotated == first {
+		t.Errorf("repository has not been initialized again after the GPG key changed")
+	}
+	if _, err := os.Stat(first.directory); !os.IsNotExist(err) {
+		t.Errorf("previous clone '%s' has not been removed", first.directory)
+	}
+
+	repositoryInitialization.GpgKeyReference.GpgKeyRefKey = "other.key"
+	if initialize() == rotated {
+		t.Errorf("repository has not been initialized again after the GPG key reference changed")
+	}
+}
+
+func TestInitializeRepositoryRemovesPreviousCloneWhenInitializingAgainFails(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+
+	client := &gpgAgentTestClient{}
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       client,
+	}
+
+	repositoryInitialization := &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			RepositoryURL:  repoDir,
+			Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		},
+	}
+
+	response, err := r.InitializeRepository(context.Background(), repositoryInitialization)
+	if err != nil || !response.Successful {
+		t.Fatalf("not able to initialize repository: %v, %s", err, response.GetErrorMessage())
+	}
+	previous := r.Repositories[repoDir]
+	previousGnupgHome := client.gnupgHome
+
+	repositoryInitialization.Repository.StorePath = "does-not-exist"
+	response, err = r.InitializeRepository(context.Background(), repositoryInitialization)
+	if err == nil || response.Successful || !strings.Contains(response.GetErrorMessage(), "removed the clone initialized with the previous parameters") {
+		t.Errorf("InitializeRepository() = %v, %v, wanted an error reporting the removal of the previous clone", response, err)
+	}
+
+	if _, ok := r.Repositories[repoDir]; ok {
+		t.Errorf("repository initialized with the previous parameters is still registered")
+	}
+	for _, directory := range []string{previous.directory, previous.homeDirectory} {
+		if _, err := os.Stat(directory); !os.IsNotExist(err) {
+			t.Errorf("directory '%s' has not been removed", directory)
+		}
+	}
+	for i := 0; i < 50 && gpgAgentRunning(t, previousGnupgHome); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if gpgAgentRunning(t, previousGnupgHome) {
+		t.Errorf("gpg-agent of the previous GnuPG home '%s' is still running", previousGnupgHome)
+	}
+}
@@ -0,0 +1,143 @@
+package gopass_repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// mountedRepo is a repository mounted into the store of another repository, like 'gopass mounts add' does.
+type mountedRepo struct {
+	mountPoint string
+	url        string
+	ref        string
+	directory  string
+	repository *git.Repository
+}
+
+// validateMounts ensures every mount has a repository and a unique relative mount point.
+func validateMounts(mounts []*gopass_repository.Mount) error {
+	mountPoints := make(map[string]bool, len(mounts))
+	for _, mount := range mounts {
+		if mount.RepositoryURL == "" {
+			return fmt.Errorf("mount '%s' requires a repository URL", mount.MountPoint)
+		}
+
+		mountPoint := strings.Trim(mount.MountPoint, "/")
+		if mountPoint == "" || mountPoint != mount.MountPoint {
+			return fmt.Errorf("invalid mount point '%s' of repository '%s'", mount.MountPoint, mount.RepositoryURL)
+		}
+		for _, segment := range strings.Split(mountPoint, "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				return fmt.Errorf("invalid mount point '%s' of repository '%s'", mount.MountPoint, mount.RepositoryURL)
+			}
+		}
+
+		if mountPoints[mountPoint] {
+			return fmt.Errorf("mount point '%s' is used more than once", mountPoint)
+		}
+		mountPoints[mountPoint] = true
+	}
+	return nil
+}
+
+// cloneMounts clones the repositories of all mounts. Already cloned mounts are removed if one of them fails.
+func (r *RepositoryServer) cloneMounts(ctx context.Context, mounts []*gopass_repository.Mount) ([]*mountedRepo, error) {
+	err := validateMounts(mounts)
+	if err != nil {
+		log.Printf("invalid mounts: %v", err)
+		return nil, err
+	}
+
+	mountedRepos := make([]*mountedRepo, 0, len(mounts))
+	for _, mount := range mounts {
+		mounted, err := r.cloneMount(ctx, mount)
+		if err != nil {
+			removeMounts(mountedRepos)
+			return nil, err
+		}
+		mountedRepos = append(mountedRepos, mounted)
+	}
+	return mountedRepos, nil
+}
+
+func (r *RepositoryServer) cloneMount(ctx context.Context, mount *gopass_repository.Mount) (*mountedRepo, error) {
+	credentials, err := r.Client.GetRepositoryCredentials(ctx, mount.Authentication)
+	if err != nil {
+		log.Printf("error fetching credentials of mount '%s': %v", mount.MountPoint, err)
+		return nil, err
+	}
+
+	directory, err := ioutil.TempDir("", "gopass-mount")
+	if err != nil {
+		log.Printf("not able to create directory of mount '%s': %v", mount.MountPoint, err)
+		return nil, err
+	}
+
+	repository, err := cloneGopassRepo(mount.RepositoryURL, mount.Ref, directory, credentials)
+	if err != nil {
+		log.Printf("not able clone repository of mount '%s' with URL %s: %v", mount.MountPoint, mount.RepositoryURL, err)
+		removeDirectory(directory)
+		return nil, err
+	}
+
+	return &mountedRepo{
+		mountPoint: mount.MountPoint,
+		url:        mount.RepositoryURL,
+		ref:        mount.Ref,
+		directory:  directory,
+		repository: repository,
+	}, nil
+}
+
+// updateMounts checks out the latest state of every mount. Mounts are fixed when the repository is initialized.
+func (r *RepositoryServer) updateMounts(ctx context.Context, mountedRepos []*mountedRepo, mounts []*gopass_repository.Mount) error {
+	for _, mounted := range mountedRepos {
+		mount := findMount(mounts, mounted.mountPoint)
+		if mount == nil || mount.RepositoryURL != mounted.url || mount.Ref != mounted.ref {
+			log.Printf("mount '%s' has been changed, the repository needs to be initialized again", mounted.mountPoint)
+			continue
+		}
+
+		credentials, err := r.Client.GetRepositoryCredentials(ctx, mount.Authentication)
+		if err != nil {
+			log.Printf("error fetching credentials of mount '%s': %v", mount.MountPoint, err)
+			return err
+		}
+
+		err = updateGopassRepo(mounted.repository, mount.RepositoryURL, mounted.ref, credentials)
+		if err != nil {
+			log.Printf("unable to update mount '%s': %v", mount.MountPoint, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func findMount(mounts []*gopass_repository.Mount, mountPoint string) *gopass_repository.Mount {
+	for _, mount := range mounts {
+		if mount.MountPoint == mountPoint {
+			return mount
+		}
+	}
+	return nil
+}
+
+// mountPaths returns the local directories of the mounts by their mount points, as expected by the gopass configuration.
+func mountPaths(mountedRepos []*mountedRepo) map[string]string {
+	paths := make(map[string]string, len(mountedRepos))
+	for _, mounted := range mountedRepos {
+		paths[mounted.mountPoint] = mounted.directory
+	}
+	return paths
+}
+
+func removeMounts(mountedRepos []*mountedRepo) {
+	for _, mounted := range mountedRepos {
+		removeDirectory(mounted.directory)
+	}
+}
@@ -0,0 +1,103 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestValidateMounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		mounts  []*gopass_repository.Mount
+		wantErr bool
+	}{
+		{name: "no mounts", mounts: nil, wantErr: false},
+		{
+			name: "valid mounts",
+			mounts: []*gopass_repository.Mount{
+				{MountPoint: "shared", RepositoryURL: "https://example.com/shared.git"},
+				{MountPoint: "team/a", RepositoryURL: "https://example.com/team-a.git"},
+			},
+			wantErr: false,
+		},
+		{name: "missing repository URL", mounts: []*gopass_repository.Mount{{MountPoint: "shared"}}, wantErr: true},
+		{name: "missing mount point", mounts: []*gopass_repository.Mount{{RepositoryURL: "https://example.com/shared.git"}}, wantErr: true},
+		{name: "absolute mount point", mounts: []*gopass_repository.Mount{{MountPoint: "/shared", RepositoryURL: "https://example.com/shared.git"}}, wantErr: true},
+		{name: "mount point with trailing slash", mounts: []*gopass_repository.Mount{{MountPoint: "shared/", RepositoryURL: "https://example.com/shared.git"}}, wantErr: true},
+		{name: "mount point leaving the store", mounts: []*gopass_repository.Mount{{MountPoint: "shared/../..", RepositoryURL: "https://example.com/shared.git"}}, wantErr: true},
+		{
+			name: "duplicate mount point",
+			mounts: []*gopass_repository.Mount{
+				{MountPoint: "shared", RepositoryURL: "https://example.com/shared.git"},
+				{MountPoint: "shared", RepositoryURL: "https://example.com/other.git"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMounts(tt.mounts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitializeRepositoryWithMounts(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+	mountDir := initializeTestRepository(t)
+
+	r := RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       &cluster.KubernetesTestClient{},
+	}
+
+	repository := &gopass_repository.Repository{
+		RepositoryURL:  repoDir,
+		Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		Mounts: []*gopass_repository.Mount{
+			{
+				MountPoint:     "shared",
+				RepositoryURL:  mountDir,
+				Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+			},
+		},
+	}
+
+	response, err := r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{Repository: repository})
+	if err != nil || !response.Successful {
+		t.Errorf("not able to initialize repository: %v, %s", err, response.GetErrorMessage())
+		return
+	}
+	repo := r.Repositories[repoDir]
+	defer repo.remove()
+
+	entries, err := repo.store.List(context.Background())
+	if err != nil {
+		t.Errorf("not able to list entries: %v", err)
+		return
+	}
+	sort.Strings(entries)
+
+	wantedEntries := []string{"shared/testpwd", "testpwd"}
+	if !reflect.DeepEqual(entries, wantedEntries) {
+		t.Errorf("store contains %v, wanted %v", entries, wantedEntries)
+	}
+
+	response, err = r.UpdateRepository(context.Background(), repository)
+	if err != nil || !response.Successful {
+		t.Errorf("not able to update repository: %v, %s", err, response.GetErrorMessage())
+		return
+	}
+
+	repo.remove()
+	if _, err := os.Stat(repo.mounts[0].directory); !os.IsNotExist(err) {
+		t.Errorf("directory of mount '%s' has not been removed", repo.mounts[0].directory)
+	}
+}
@@ -0,0 +1,81 @@
+package gopass_repository
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	keyNamingReplace         = "Replace"
+	keyNamingKubernetesLegal = "KubernetesLegal"
+	keyNamingBasename        = "Basename"
+	keyNamingStripPrefix     = "StripPrefix"
+)
+
+const (
+	collisionPolicyFail      = "Fail"
+	collisionPolicyKeepFirst = "KeepFirst"
+	collisionPolicyKeepLast  = "KeepLast"
+)
+
+var nonAlphanumeric = regexp.MustCompile("[^a-zA-Z0-9]+")
+var illegalKeyCharacters = regexp.MustCompile("[^-._a-zA-Z0-9]+")
+
+// keyNaming derives the key inside a Secret from the name of a gopass entry.
+type keyNaming struct {
+	strategy string
+	prefix   string
+}
+
+func newKeyNaming(strategy string, prefix string) (keyNaming, error) {
+	switch strategy {
+	case "":
+		strategy = keyNamingReplace
+	case keyNamingReplace, keyNamingKubernetesLegal, keyNamingBasename:
+	case keyNamingStripPrefix:
+		if prefix == "" {
+			return keyNaming{}, fmt.Errorf("key naming strategy '%s' requires a prefix", strategy)
+		}
+	default:
+		return keyNaming{}, fmt.Errorf("unknown key naming strategy '%s'", strategy)
+	}
+
+	return keyNaming{
+		strategy: strategy,
+		prefix:   strings.TrimSuffix(prefix, "/") + "/",
+	}, nil
+}
+
+func (k keyNaming) key(name string) string {
+	switch k.strategy {
+	case keyNamingKubernetesLegal:
+		return kubernetesLegal(name)
+	case keyNamingBasename:
+		return kubernetesLegal(path.Base(name))
+	case keyNamingStripPrefix:
+		return kubernetesLegal(strings.TrimPrefix(name, k.prefix))
+	default:
+		return rename(name)
+	}
+}
+
+// rename replaces every run of non-alphanumeric characters with '-'.
+func rename(name string) string {
+	return nonAlphanumeric.ReplaceAllString(name, "-")
+}
+
+// kubernetesLegal keeps all characters allowed in keys of a Secret and replaces every other run of characters with '-'.
+func kubernetesLegal(name string) string {
+	return illegalKeyCharacters.ReplaceAllString(name, "-")
+}
+
+func validateCollisionPolicy(policy string) error {
+	switch policy {
+	case "", collisionPolicyFail, collisionPolicyKeepFirst, collisionPolicyKeepLast:
+		return nil
+	default:
+		return fmt.Errorf("unknown collision policy '%s'", policy)
+	}
+}
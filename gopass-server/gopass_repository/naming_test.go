@@ -0,0 +1,48 @@
+package gopass_repository
+
+import (
+	"testing"
+)
+
+func TestKeyNaming(t *testing.T) {
+	tests := []struct {
+		strategy string
+		prefix   string
+		name     string
+		want     string
+	}{
+		{strategy: "", name: "team-a/prod/db_password.txt", want: "team-a-prod-db-password-txt"},
+		{strategy: keyNamingReplace, name: "team-a/prod/db_password.txt", want: "team-a-prod-db-password-txt"},
+		{strategy: keyNamingKubernetesLegal, name: "team-a/prod/db_password.txt", want: "team-a-prod-db_password.txt"},
+		{strategy: keyNamingKubernetesLegal, name: "team-a/prod/db password", want: "team-a-prod-db-password"},
+		{strategy: keyNamingBasename, name: "team-a/prod/db_password.txt", want: "db_password.txt"},
+		{strategy: keyNamingStripPrefix, prefix: "team-a/prod", name: "team-a/prod/db_password.txt", want: "db_password.txt"},
+		{strategy: keyNamingStripPrefix, prefix: "team-a/", name: "team-a/prod/db_password.txt", want: "prod-db_password.txt"},
+		{strategy: keyNamingStripPrefix, prefix: "team-a", name: "team-b/token", want: "team-b-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy+" "+tt.name, func(t *testing.T) {
+			naming, err := newKeyNaming(tt.strategy, tt.prefix)
+			if err != nil {
+				t.Errorf("newKeyNaming() error = %v", err)
+				return
+			}
+
+			if got := naming.key(tt.name); got != tt.want {
+				t.Errorf("key() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewKeyNamingWithInvalidConfiguration(t *testing.T) {
+	_, err := newKeyNaming("Lowercase", "")
+	if err == nil || err.Error() != "unknown key naming strategy 'Lowercase'" {
+		t.Errorf("newKeyNaming() error = %v, expected unknown strategy", err)
+	}
+
+	_, err = newKeyNaming(keyNamingStripPrefix, "")
+	if err == nil || err.Error() != "key naming strategy 'StripPrefix' requires a prefix" {
+		t.Errorf("newKeyNaming() error = %v, expected missing prefix", err)
+	}
+}
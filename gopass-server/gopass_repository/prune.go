@@ -0,0 +1,196 @@
+package gopass_repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"log"
+	"sort"
+)
+
+const (
+	prunePolicyPrune = "Prune"
+	prunePolicyKeep  = "Keep"
+)
+
+// deletionPolicyOrphan leaves the Secrets and ConfigMaps in the cluster instead of deleting them.
+const deletionPolicyOrphan = "Orphan"
+
+func validatePrunePolicy(policy string) error {
+	switch policy {
+	case "", prunePolicyPrune, prunePolicyKeep:
+		return nil
+	default:
+		return fmt.Errorf("unknown prune policy '%s'", policy)
+	}
+}
+
+// managedData returns the data of the resource of the target owned by the operator. Keys added by others are ignored.
+func (r *RepositoryServer) managedData(ctx context.Context, target secretTarget) (map[string]string, error) {
+	data := make(map[string]string)
+
+	if target.kind == targetKindConfigMap {
+		configMap, err := getConfigMap(ctx, r.KubernetesClient, target.name)
+		if errors.IsNotFound(err) {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range managedKeys(configMap.ManagedFields, r.legacyFieldManagers()) {
+			if value, ok := configMap.Data[key]; ok {
+				data[key] = value
+			}
+		}
+		return data, nil
+	}
+
+	secret, err := getSecretMap(ctx, r.KubernetesClient, target.name)
+	if errors.IsNotFound(err) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range managedKeys(secret.ManagedFields, r.legacyFieldManagers()) {
+		if value, ok := secret.Data[key]; ok {
+			data[key] = string(value)
+		}
+	}
+	return data, nil
+}
+
+// keepStaleKeys copies keys owned by the operator whose entries disappeared into the new data.
+// The copied keys are reported as stale.
+func (r *RepositoryServer) keepStaleKeys(ctx context.Context, target secretTarget, data map[string]string) ([]*gopass_repository.StaleKey, error) {
+	existing, err := r.managedData(ctx, target)
+	if err != nil {
+		log.Printf("unable to fetch existing data of '%s': %v\n", target.name, err)
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	for key, value := range existing {
+		if _, ok := data[key]; ok {
+			continue
+		}
+		data[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	staleKeys := make([]*gopass_repository.StaleKey, 0, len(keys))
+	for _, key := range keys {
+		staleKeys = append(staleKeys, &gopass_repository.StaleKey{
+			SecretName: &gopass_repository.NamespacedName{
+				Namespace: target.name.Namespace,
+				Name:      target.name.Name,
+			},
+			Key: key,
+		})
+	}
+	return staleKeys, nil
+}
+
+// ownedResources returns the Secrets and ConfigMaps labeled as written for the repository, including the ones which
+// are no longer targeted.
+func (r *RepositoryServer) ownedResources(ctx context.Context, repository *gopass_repository.Repository) ([]targetResource, error) {
+	owner := ownerLabels(repository)
+	if owner == nil {
+		return nil, nil
+	}
+	namespace := repository.SecretName.Namespace
+	listOptions := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(owner).String()}
+
+	secrets, err := r.KubernetesClient.CoreV1().Secrets(namespace).List(ctx, listOptions)
+	if err != nil {
+		log.Printf("unable to list secrets of repository: %v\n", err)
+		return nil, err
+	}
+	configMaps, err := r.KubernetesClient.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+	if err != nil {
+		log.Printf("unable to list config maps of repository: %v\n", err)
+		return nil, err
+	}
+
+	resources := make([]targetResource, 0, len(secrets.Items)+len(configMaps.Items))
+	for _, secret := range secrets.Items {
+		resources = append(resources, targetResource{kind: targetKindSecret, name: types.NamespacedName{Namespace: namespace, Name: secret.Name}})
+	}
+	for _, configMap := range configMaps.Items {
+		resources = append(resources, targetResource{kind: targetKindConfigMap, name: types.NamespacedName{Namespace: namespace, Name: configMap.Name}})
+	}
+	return resources, nil
+}
+
+// pruneRemovedTargets deletes the Secrets and ConfigMaps written for the repository which are no longer targeted, e.g.
+// after a target has been removed or its kind changed. With the prune policy Keep they are left untouched and returned,
+// so they can be reported. With the deletion policy Orphan they are left in the cluster without the owner labels.
+func (r *RepositoryServer) pruneRemovedTargets(ctx context.Context, repository *gopass_repository.Repository, targets []secretTarget) ([]*gopass_repository.NamespacedName, error) {
+	owned, err := r.ownedResources(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	targeted := make(map[targetResource]bool, len(targets))
+	for _, target := range targets {
+		targeted[targetResource{kind: target.kind, name: target.name}] = true
+	}
+
+	untargeted := make([]*gopass_repository.NamespacedName, 0)
+	for _, resource := range owned {
+		if targeted[resource] {
+			continue
+		}
+
+		switch {
+		case repository.PrunePolicy == prunePolicyKeep:
+			log.Printf("%s '%s' is no longer targeted, keeping it\n", resource.kind, resource.name)
+			untargeted = append(untargeted, &gopass_repository.NamespacedName{Namespace: resource.name.Namespace, Name: resource.name.Name})
+		case repository.DeletionPolicy == deletionPolicyOrphan:
+			log.Printf("%s '%s' is no longer targeted, orphaning it\n", resource.kind, resource.name)
+			err = r.removeOwnerLabels(ctx, resource)
+		case resource.kind == targetKindConfigMap:
+			log.Printf("%s '%s' is no longer targeted, deleting it\n", resource.kind, resource.name)
+			_, err = r.deleteConfigMap(ctx, resource.name)
+		default:
+			log.Printf("%s '%s' is no longer targeted, deleting it\n", resource.kind, resource.name)
+			_, err = r.deleteSecretMap(ctx, resource.name)
+		}
+		if err != nil {
+			return untargeted, err
+		}
+	}
+	return untargeted, nil
+}
+
+// removeOwnerLabels releases a Secret or ConfigMap, so it is no longer pruned or deleted for the repository.
+func (r *RepositoryServer) removeOwnerLabels(ctx context.Context, resource targetResource) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				repositoryNameLabel:      nil,
+				repositoryNamespaceLabel: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	options := metav1.PatchOptions{FieldManager: fieldManager}
+	if resource.kind == targetKindConfigMap {
+		_, err = r.KubernetesClient.CoreV1().ConfigMaps(resource.name.Namespace).Patch(ctx, resource.name.Name, types.MergePatchType, patch, options)
+	} else {
+		_, err = r.KubernetesClient.CoreV1().Secrets(resource.name.Namespace).Patch(ctx, resource.name.Name, types.MergePatchType, patch, options)
+	}
+	if err != nil {
+		log.Printf("unable to remove owner labels of %s '%s': %v\n", resource.kind, resource.name, err)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/gopasspw/gopass/pkg/gopass/apimock"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateAllPasswordsWithPrunePolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		prunePolicy     string
+		wanted          map[string]string
+		wantedStaleKeys []string
+	}{
+		{
+			name:            "removed entries are pruned by default",
+			prunePolicy:     "",
+			wanted:          map[string]string{"current": "current password"},
+			wantedStaleKeys: []string{},
+		},
+		{
+			name:            "removed entries are kept and reported as stale",
+			prunePolicy:     prunePolicyKeep,
+			wanted:          map[string]string{"current": "current password", "removed": "old password"},
+			wantedStaleKeys: []string{"removed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := apimock.New()
+			err := store.Set(context.Background(), "current", &apimock.Secret{Buf: []byte("current password")})
+			if err != nil {
+				t.Errorf("unable to set key in store: %v", err)
+				return
+			}
+
+			kubernetesClient := newApplyClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:     "testNamespace",
+					Name:          "someSecret",
+					ManagedFields: []metav1.ManagedFieldsEntry{managedFieldsEntry(fieldManager, []string{"current", "removed"})},
+				},
+				Data: map[string][]byte{
+					"current": []byte("outdated password"),
+					"removed": []byte("old password"),
+				},
+			})
+			r := &RepositoryServer{
+				Repositories: map[string]*gopassRepo{
+					"testUrl": {store: store},
+				},
+				Client:           &cluster.KubernetesTestClient{},
+				KubernetesClient: kubernetesClient,
+			}
+
+			response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+				RepositoryURL: "testUrl",
+				SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+				PrunePolicy:   tt.prunePolicy,
+			})
+			if err != nil {
+				t.Errorf("UpdateAllPasswords() error = %v", err)
+				return
+			}
+
+			applied := appliedSecretData(t, kubernetesClient.lastApply(t, "secrets", "someSecret"))
+			if !reflect.DeepEqual(applied, tt.wanted) {
+				t.Errorf("secret was applied with %v, wanted %v", applied, tt.wanted)
+			}
+
+			staleKeys := make([]string, 0)
+			for _, staleKey := range response.StaleKeys {
+				staleKeys = append(staleKeys, staleKey.Key)
+			}
+			if !reflect.DeepEqual(staleKeys, tt.wantedStaleKeys) {
+				t.Errorf("stale keys were %v, wanted %v", staleKeys, tt.wantedStaleKeys)
+			}
+		})
+	}
+}
+
+func TestValidatePrunePolicy(t *testing.T) {
+	for _, policy := range []string{"", prunePolicyPrune, prunePolicyKeep} {
+		if err := validatePrunePolicy(policy); err != nil {
+			t.Errorf("validatePrunePolicy(%s) error = %v", policy, err)
+		}
+	}
+	if err := validatePrunePolicy("Delete"); err == nil {
+		t.Errorf("validatePrunePolicy() did not return an error for an unknown policy")
+	}
+}
@@ -0,0 +1,63 @@
+package gopass_repository
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	config2 "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"log"
+)
+
+// checkoutRef fetches all branches and tags of the repository and checks out the given branch, tag or commit.
+// The worktree is left in a detached state, as it is only read. The CA bundle is trusted for HTTPS repositories.
+func checkoutRef(repository *git.Repository, auth transport.AuthMethod, caBundle []byte, ref string) error {
+	log.Printf("fetching repository to check out '%s'\n", ref)
+	err := repository.Fetch(&git.FetchOptions{
+		Auth:     auth,
+		CABundle: caBundle,
+		RefSpecs: []config2.RefSpec{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Force: true,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		log.Printf("unable to fetch repository: %v\n", err)
+		return err
+	}
+
+	hash, err := resolveRef(repository, ref)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		log.Printf("unable to fetch worktree of repository: %v\n", err)
+		return err
+	}
+
+	err = worktree.Checkout(&git.CheckoutOptions{
+		Hash:  hash,
+		Force: true,
+	})
+	if err != nil {
+		log.Printf("unable to check out '%s': %v\n", ref, err)
+		return err
+	}
+	log.Printf("checked out '%s' at %s\n", ref, hash)
+	return nil
+}
+
+// resolveRef returns the commit of a branch, tag or (abbreviated) commit hash. Branches are resolved using the remote
+// branches, as the local ones are not updated.
+func resolveRef(repository *git.Repository, ref string) (plumbing.Hash, error) {
+	for _, revision := range []string{"refs/remotes/origin/" + ref, ref} {
+		hash, err := repository.ResolveRevision(plumbing.Revision(revision))
+		if err == nil {
+			return *hash, nil
+		}
+	}
+	return plumbing.ZeroHash, fmt.Errorf("ref '%s' not found in repository", ref)
+}
@@ -0,0 +1,206 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"testing"
+)
+
+func checkoutBranch(t *testing.T, repository *git.Repository, branch string, create bool) {
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatalf("unable to get worktree: %v", err)
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	})
+	if err != nil {
+		t.Fatalf("unable to check out branch '%s': %v", branch, err)
+	}
+}
+
+func assertHead(t *testing.T, repository *git.Repository, wanted string) {
+	head, err := headCommit(repository)
+	if err != nil {
+		t.Errorf("unable to resolve HEAD: %v", err)
+		return
+	}
+	if head != wanted {
+		t.Errorf("HEAD is at '%s', wanted '%s'", head, wanted)
+	}
+}
+
+func TestCheckoutRef(t *testing.T) {
+	remoteDir, remote, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+
+	initial := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "initial"})
+	_, err = remote.CreateTag("v1", plumbing.NewHash(initial), nil)
+	if err != nil {
+		t.Fatalf("unable to create tag: %v", err)
+	}
+
+	checkoutBranch(t, remote, "staging", true)
+	staging := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "staging"})
+	checkoutBranch(t, remote, "master", false)
+	commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "master"})
+
+	tests := []struct {
+		ref    string
+		wanted string
+	}{
+		{ref: "staging", wanted: staging},
+		{ref: "v1", wanted: initial},
+		{ref: staging[:8], wanted: staging},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			repository, err := cloneGopassRepo(remoteDir, tt.ref, t.TempDir(), cluster.Credentials{})
+			if err != nil {
+				t.Errorf("unable to clone repository: %v", err)
+				return
+			}
+			assertHead(t, repository, tt.wanted)
+
+			err = updateGopassRepo(repository, remoteDir, tt.ref, cluster.Credentials{})
+			if err != nil {
+				t.Errorf("updateGopassRepo() error = %v", err)
+				return
+			}
+			assertHead(t, repository, tt.wanted)
+		})
+	}
+
+	repository, err := cloneGopassRepo(remoteDir, "staging", t.TempDir(), cluster.Credentials{})
+	if err != nil {
+		t.Errorf("unable to clone repository: %v", err)
+		return
+	}
+	checkoutBranch(t, remote, "staging", false)
+	updatedStaging := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "updated staging"})
+	checkoutBranch(t, remote, "master", false)
+
+	err = updateGopassRepo(repository, remoteDir, "staging", cluster.Credentials{})
+	if err != nil {
+		t.Errorf("updateGopassRepo() error = %v", err)
+		return
+	}
+	assertHead(t, repository, updatedStaging)
+
+	_, err = cloneGopassRepo(remoteDir, "does-not-exist", t.TempDir(), cluster.Credentials{})
+	if err == nil {
+		t.Errorf("cloneGopassRepo() did not return an error for an unknown ref")
+	}
+}
+
+func TestInitializeRepositoryAgainWhenRefChanges(t *testing.T) {
+	remoteDir, remote, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+
+	commitFiles(t, remote, remoteDir, map[string]string{".gpg-id": "0123456789ABCDEF", "database.gpg": "initial"})
+	checkoutBranch(t, remote, "staging", true)
+	staging := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "staging"})
+	checkoutBranch(t, remote, "master", false)
+	master := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "master"})
+
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       &cluster.KubernetesTestClient{},
+	}
+	defer func() {
+		for _, repo := range r.Repositories {
+			repo.remove()
+		}
+	}()
+
+	steps := []struct {
+		ref    string
+		wanted string
+	}{
+		{ref: "staging", wanted: staging},
+		{ref: "", wanted: master},
+		{ref: "staging", wanted: staging},
+	}
+	var previous *gopassRepo
+	for _, step := range steps {
+		response, err := r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{
+			Repository: &gopass_repository.Repository{
+				RepositoryURL:  remoteDir,
+				Ref:            step.ref,
+				Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+			},
+		})
+		if err != nil || !response.Successful {
+			t.Errorf("not able to initialize repository with ref '%s': %v, %s", step.ref, err, response.GetErrorMessage())
+			return
+		}
+
+		repo := r.Repositories[remoteDir]
+		if repo == previous {
+			t.Errorf("repository has not been cloned again after the ref changed to '%s'", step.ref)
+		}
+		assertHead(t, repo.repository, step.wanted)
+		previous = repo
+	}
+}
+
+func TestUpdateRepositoryRejectsChangedRef(t *testing.T) {
+	remoteDir, remote, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+
+	commitFiles(t, remote, remoteDir, map[string]string{".gpg-id": "0123456789ABCDEF", "database.gpg": "initial"})
+	checkoutBranch(t, remote, "staging", true)
+	staging := commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "staging"})
+	checkoutBranch(t, remote, "master", false)
+	commitFiles(t, remote, remoteDir, map[string]string{"database.gpg": "master"})
+
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       &cluster.KubernetesTestClient{},
+	}
+	defer func() {
+		for _, repo := range r.Repositories {
+			repo.remove()
+		}
+	}()
+
+	repository := &gopass_repository.Repository{
+		RepositoryURL:  remoteDir,
+		Ref:            "staging",
+		Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+	}
+	response, err := r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{Repository: repository})
+	if err != nil || !response.Successful {
+		t.Errorf("not able to initialize repository: %v, %s", err, response.GetErrorMessage())
+		return
+	}
+
+	for _, ref := range []string{"", "master"} {
+		response, err = r.UpdateRepository(context.Background(), &gopass_repository.Repository{
+			RepositoryURL:  remoteDir,
+			Ref:            ref,
+			Authentication: repository.Authentication,
+		})
+		if err == nil || response.Successful {
+			t.Errorf("UpdateRepository() with ref '%s' succeeded, wanted the changed ref to be rejected", ref)
+		}
+		assertHead(t, r.Repositories[remoteDir].repository, staging)
+	}
+
+	response, err = r.UpdateRepository(context.Background(), repository)
+	if err != nil || !response.Successful {
+		t.Errorf("UpdateRepository() error = %v, %s", err, response.GetErrorMessage())
+		return
+	}
+	assertHead(t, r.Repositories[remoteDir].repository, staging)
+}
@@ -4,137 +4,304 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/api"
 	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
-	ssh_2 "golang.org/x/crypto/ssh"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
 type config struct {
-	Path string `yaml:"path"`
+	Path   string            `yaml:"path"`
+	Mounts map[string]string `yaml:"mounts,omitempty"`
 }
 
-func (r *RepositoryServer) initializeRepository(ctx context.Context, repositoryInitialization *gopass_repository.RepositoryInitialization) error {
+// gopassEnvironmentLock serializes the creation of gopass clients, as gopass reads its configuration from the environment.
+var gopassEnvironmentLock sync.Mutex
+
+// initializeRepository clones the repository unless it has already been initialized with the same parameters. If the
+// parameters changed, the repository is cloned again and the previous clone is removed. If cloning it again fails, the
+// previous clone is removed as well, so neither stale credentials nor a GPG key which has been rotated are used any
+// longer.
+func (r *RepositoryServer) initializeRepository(ctx context.Context, repositoryInitialization *gopass_repository.RepositoryInitialization) (string, error) {
 	log.Printf("InitializeRepository called with: %s", (*repositoryInitialization).Repository.RepositoryURL)
 
 	repository := repositoryInitialization.Repository
-	_, ok := (r.Repositories)[repository.RepositoryURL]
-	if ok {
+	fingerprint, err := r.initializationFingerprint(ctx, repositoryInitialization)
+	if err != nil {
+		return "", err
+	}
+
+	existingRepository, ok := r.getRepository(repository.RepositoryURL)
+	if ok && existingRepository.fingerprint == fingerprint {
 		log.Printf("repository with URL '%s' already initialized", repository.RepositoryURL)
-		return nil
+		return existingRepository.headCommit()
+	}
+	if ok {
+		log.Printf("parameters of repository with URL '%s' changed, initializing it again", repository.RepositoryURL)
+	}
+
+	gopassRepository, err := r.createGopassRepository(ctx, repositoryInitialization)
+	if err != nil {
+		if ok && r.unregisterRepository(repository.RepositoryURL, existingRepository) {
+			log.Printf("removing clone of repository with URL '%s' initialized with the previous parameters", repository.RepositoryURL)
+			existingRepository.lock.Lock()
+			existingRepository.remove()
+			existingRepository.lock.Unlock()
+			return "", fmt.Errorf("unable to initialize repository with changed parameters, removed the clone initialized with the previous parameters: %v", err)
+		}
+		return "", err
+	}
+
+	gopassRepository.fingerprint = fingerprint
+
+	registeredRepository := r.replaceRepository(repository.RepositoryURL, existingRepository, gopassRepository)
+	if registeredRepository != gopassRepository {
+		log.Printf("repository with URL '%s' has been initialized concurrently, removing the new clone", repository.RepositoryURL)
+		gopassRepository.remove()
+	} else if existingRepository != nil {
+		log.Printf("removing previous clone of repository with URL '%s'", repository.RepositoryURL)
+		existingRepository.lock.Lock()
+		existingRepository.remove()
+		existingRepository.lock.Unlock()
 	}
 
+	return registeredRepository.headCommit()
+}
+
+// createGopassRepository clones the repository and its mounts and imports the GPG key into a new home directory.
+func (r *RepositoryServer) createGopassRepository(ctx context.Context, repositoryInitialization *gopass_repository.RepositoryInitialization) (*gopassRepo, error) {
+	repository := repositoryInitialization.Repository
+
 	credentials, err := r.Client.GetRepositoryCredentials(ctx, repository.Authentication)
 	if err != nil {
 		log.Printf("error initializing repository: %v", err)
-		return err
+		return nil, err
+	}
+
+	homeDirectory, err := createHomeDirectory()
+	if err != nil {
+		return nil, err
 	}
 
-	err = r.Client.GetGpgKey(ctx, repository.Authentication.Namespace, repositoryInitialization.GpgKeyReference)
+	err = r.Client.GetGpgKey(ctx, repository.Authentication.Namespace, gnupgHome(homeDirectory), repositoryInitialization.GpgKeyReference)
 	if err != nil {
 		log.Printf("error fetching gpgKey: %v", err)
-		return err
+		removeHomeDirectory(homeDirectory)
+		return nil, err
 	}
 
-	gopassRepository, err := initializeNewGopassRepository(repository.RepositoryURL, credentials)
+	mounts, err := r.cloneMounts(ctx, repository.Mounts)
 	if err != nil {
-		log.Printf("error initializing repository: %v", err)
-		return err
+		log.Printf("error cloning mounts: %v", err)
+		removeHomeDirectory(homeDirectory)
+		return nil, err
 	}
 
-	(r.Repositories)[repository.RepositoryURL] = gopassRepository
+	gopassRepository, err := initializeNewGopassRepository(repository.RepositoryURL, repository.Ref, repository.StorePath, credentials, homeDirectory, mounts)
+	if err != nil {
+		log.Printf("error initializing repository: %v", err)
+		removeMounts(mounts)
+		removeHomeDirectory(homeDirectory)
+		return nil, err
+	}
 
-	return nil
+	return gopassRepository, nil
 }
 
-func (r *RepositoryServer) updateRepository(ctx context.Context, repository *gopass_repository.Repository) error {
+func (r *RepositoryServer) updateRepository(ctx context.Context, repository *gopass_repository.Repository) (string, error) {
 	log.Printf("UpdateRepository called with: %s", (*repository).RepositoryURL)
 
-	repo, ok := (r.Repositories)[(*repository).RepositoryURL]
+	repo, ok := r.getRepository((*repository).RepositoryURL)
 	if !ok {
 		log.Printf("unable to find repository with with URL '%s'", (*repository).RepositoryURL)
 
-		return fmt.Errorf("unable to find repository with with URL '%s'", (*repository).RepositoryURL)
+		return "", fmt.Errorf("unable to find repository with with URL '%s'", (*repository).RepositoryURL)
 	}
 
 	credentials, err := r.Client.GetRepositoryCredentials(ctx, repository.Authentication)
 	if err != nil {
 		log.Printf("error initializing repository: %v", err)
-		return err
+		return "", err
+	}
+
+	if repository.Ref != repo.ref {
+		log.Printf("ref of repository with URL '%s' changed from '%s' to '%s'", repository.RepositoryURL, repo.ref, repository.Ref)
+		return "", fmt.Errorf("ref of repository with URL '%s' changed from '%s' to '%s', it needs to be initialized again", repository.RepositoryURL, repo.ref, repository.Ref)
 	}
 
-	err = updateGopassRepo(repo.repository, credentials.Name, credentials.Password)
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+
+	err = updateGopassRepo(repo.repository, repository.RepositoryURL, repo.ref, credentials)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	err = r.updateMounts(ctx, repo.mounts, repository.Mounts)
+	if err != nil {
+		return "", err
 	}
 	log.Printf("synced repository with URL '%s'", (*repository).RepositoryURL)
 
-	return nil
+	return headCommit(repo.repository)
 }
 
-func initializeNewGopassRepository(repositoryUrl string, credentials cluster.Secret) (*gopassRepo, error) {
+// initializeNewGopassRepository clones the repository, checks out the ref if given and creates a gopass client using the
+// configuration and GnuPG home inside the given home directory of the repository. The store is located at storePath
+// within the clone. The already cloned mounts are configured as sub-stores.
+func initializeNewGopassRepository(repositoryUrl string, ref string, storePath string, credentials cluster.Credentials, homeDirectory string, mounts []*mountedRepo) (*gopassRepo, error) {
 	repoDir, err := ioutil.TempDir("", "gopass")
 	if err != nil {
 		log.Printf("not able to create local repository directory: %v", err)
 		return nil, err
 	}
 
-	repository, err := cloneGopassRepo(repositoryUrl, repoDir, credentials.Name, credentials.Password)
+	repository, err := cloneGopassRepo(repositoryUrl, ref, repoDir, credentials)
 	if err != nil {
 		log.Printf("not able clone gopass repository with URL %s: %v", repositoryUrl, err)
+		removeDirectory(repoDir)
 		return nil, err
 	}
 
-	store, err := createNewGopassClient(context.Background(), repoDir)
+	storeDirectory, err := storeDirectory(repoDir, storePath)
+	if err != nil {
+		log.Printf("invalid store path '%s': %v", storePath, err)
+		removeDirectory(repoDir)
+		return nil, err
+	}
+
+	store, err := createNewGopassClient(context.Background(), storeDirectory, homeDirectory, mountPaths(mounts))
 	if err != nil {
 		log.Printf("not able to create new gopass client: %v", err)
+		removeDirectory(repoDir)
 		return nil, err
 	}
 
 	gr := &gopassRepo{
-		store:      store,
-		directory:  repoDir,
-		repository: repository,
+		store:         store,
+		directory:     repoDir,
+		homeDirectory: homeDirectory,
+		storePath:     relativeStorePath(storePath),
+		ref:           ref,
+		repository:    repository,
+		mounts:        mounts,
 	}
 
 	return gr, nil
 }
 
-func cloneGopassRepo(repositoryUrl string, path string, username string, password string) (*git.Repository, error) {
-	sshPassword := ssh.Password{
-		User:     username,
-		Password: password,
-		HostKeyCallbackHelper: ssh.HostKeyCallbackHelper{
-			HostKeyCallback: ssh_2.InsecureIgnoreHostKey(),
-		},
+// storeDirectory returns the directory of the store at storePath within the clone. The store has to stay inside of the
+// clone and contain a .gpg-id.
+func storeDirectory(repoDir string, storePath string) (string, error) {
+	if storePath == "" {
+		return repoDir, nil
+	}
+
+	cleanPath := filepath.Clean(storePath)
+	if filepath.IsAbs(cleanPath) || cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("store path '%s' is not inside of the repository", storePath)
+	}
+
+	directory := filepath.Join(repoDir, cleanPath)
+	_, err := os.Stat(filepath.Join(directory, ".gpg-id"))
+	if err != nil {
+		return "", fmt.Errorf("store path '%s' does not contain a .gpg-id: %v", storePath, err)
+	}
+
+	return directory, nil
+}
+
+// relativeStorePath returns the store path as used in the tree of the repository.
+func relativeStorePath(storePath string) string {
+	cleanPath := filepath.ToSlash(filepath.Clean(storePath))
+	if cleanPath == "." {
+		return ""
+	}
+	return cleanPath
+}
+
+// createHomeDirectory creates the directory holding the gopass configuration and the GnuPG home of a single repository.
+func createHomeDirectory() (string, error) {
+	homeDirectory, err := ioutil.TempDir("", "gopass-home")
+	if err != nil {
+		log.Printf("not able to create home directory: %v", err)
+		return "", err
+	}
+
+	err = os.Mkdir(gnupgHome(homeDirectory), 0700)
+	if err != nil {
+		log.Printf("not able to create GnuPG home directory: %v", err)
+		removeDirectory(homeDirectory)
+		return "", err
+	}
+
+	return homeDirectory, nil
+}
+
+// removeHomeDirectory stops the gpg-agent of the GnuPG home, so no key material is kept in memory, and removes the
+// home directory.
+func removeHomeDirectory(homeDirectory string) {
+	stopGpgAgent(gnupgHome(homeDirectory))
+	removeDirectory(homeDirectory)
+}
+
+func gnupgHome(homeDirectory string) string {
+	return filepath.Join(homeDirectory, "gnupg")
+}
+
+// stopGpgAgent stops the gpg-agent serving the GnuPG home, if one is running.
+func stopGpgAgent(gnupgHome string) {
+	output, err := exec.Command("gpgconf", "--homedir", gnupgHome, "--kill", "gpg-agent").CombinedOutput()
+	if err != nil {
+		log.Printf("unable to stop gpg-agent: %v: %s\n", err, output)
+	}
+}
+
+func removeDirectory(directory string) {
+	err := os.RemoveAll(directory)
+	if err != nil {
+		log.Printf("failed to remove directory: %v\n", err)
+	}
+}
+
+func cloneGopassRepo(repositoryUrl string, ref string, path string, credentials cluster.Credentials) (*git.Repository, error) {
+	auth, err := createAuthMethod(repositoryUrl, credentials)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("cloning repository with URL '%s' to %s\n", repositoryUrl, path)
 	repository, err := git.PlainClone(path, false, &git.CloneOptions{
 		URL:      repositoryUrl,
 		Progress: os.Stdout,
-		Auth:     &sshPassword,
+		Auth:     auth,
+		CABundle: credentials.CABundle,
 	})
+	if err != nil || ref == "" {
+		return repository, err
+	}
+
+	err = checkoutRef(repository, auth, credentials.CABundle, ref)
 	return repository, err
 }
 
-func createNewGopassClient(ctx context.Context, path string) (gopass.Store, error) {
-	file, err := ioutil.TempFile("", "*config.yml")
-	if err != nil {
-		log.Printf("not able to create temporary configuration file %v\n", err)
-		return nil, err
-	}
-	defer removeFile(file)
-	log.Printf("created temporary configuration file: %s\n", file.Name())
+// createNewGopassClient creates a gopass client for the store at path. The mounts map mount points to the paths of
+// further stores.
+func createNewGopassClient(ctx context.Context, path string, homeDirectory string, mounts map[string]string) (gopass.Store, error) {
+	configFile := filepath.Join(homeDirectory, "config.yml")
 
 	c := config{
-		Path: path,
+		Path:   path,
+		Mounts: mounts,
 	}
 
 	marshalledConfig, err := yaml.Marshal(&c)
@@ -143,19 +310,24 @@ func createNewGopassClient(ctx context.Context, path string) (gopass.Store, erro
 		return nil, err
 	}
 
-	_, err = file.Write(marshalledConfig)
+	err = ioutil.WriteFile(configFile, marshalledConfig, 0600)
 	if err != nil {
 		log.Printf("not able to write configuration file: %v\n", err)
 		return nil, err
 	}
+	log.Printf("created configuration file: %s\n", configFile)
+
+	gopassEnvironmentLock.Lock()
+	defer gopassEnvironmentLock.Unlock()
 
-	err = file.Close()
+	err = os.Setenv("GOPASS_CONFIG", configFile)
 	if err != nil {
-		log.Printf("not able to close configuration file: %v\n", err)
+		log.Printf("not able to set environment variable: %v\n", err)
 		return nil, err
 	}
 
-	err = os.Setenv("GOPASS_CONFIG", file.Name())
+	// the options are read once when the client is created, so every client keeps using its own GnuPG home
+	err = os.Setenv("GOPASS_GPG_OPTS", "--homedir "+gnupgHome(homeDirectory))
 	if err != nil {
 		log.Printf("not able to set environment variable: %v\n", err)
 		return nil, err
@@ -170,20 +342,34 @@ func createNewGopassClient(ctx context.Context, path string) (gopass.Store, erro
 	return store, nil
 }
 
-func removeFile(file *os.File) {
-	err := os.Remove(file.Name())
+// headCommit returns the hash of the commit HEAD points to. Repositories without any commits yield an empty hash.
+func headCommit(repository *git.Repository) (string, error) {
+	if repository == nil {
+		return "", nil
+	}
+
+	head, err := repository.Head()
 	if err != nil {
-		log.Printf("failed to remove file: %v\n", err)
+		if err == plumbing.ErrReferenceNotFound {
+			return "", nil
+		}
+		log.Printf("unable to resolve HEAD of repository: %v", err)
+		return "", err
 	}
+
+	return head.Hash().String(), nil
 }
 
-func updateGopassRepo(repository *git.Repository, username string, password string) error {
-	sshPassword := ssh.Password{
-		User:     username,
-		Password: password,
-		HostKeyCallbackHelper: ssh.HostKeyCallbackHelper{
-			HostKeyCallback: ssh_2.InsecureIgnoreHostKey(),
-		},
+// updateGopassRepo checks out the latest state of the ref. Without a ref the default branch is pulled. The ref has to be
+// the one the repository has been cloned with, as pulling the default branch requires it to still be checked out.
+func updateGopassRepo(repository *git.Repository, repositoryUrl string, ref string, credentials cluster.Credentials) error {
+	auth, err := createAuthMethod(repositoryUrl, credentials)
+	if err != nil {
+		return err
+	}
+
+	if ref != "" {
+		return checkoutRef(repository, auth, credentials.CABundle, ref)
 	}
 
 	log.Printf("pulling repository\n")
@@ -193,7 +379,8 @@ func updateGopassRepo(repository *git.Repository, username string, password stri
 		return err
 	}
 	err = worktree.Pull(&git.PullOptions{
-		Auth: &sshPassword,
+		Auth:     auth,
+		CABundle: credentials.CABundle,
 	})
 	if err != nil {
 		if err == git.NoErrAlreadyUpToDate {
@@ -11,6 +11,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -49,7 +50,7 @@ func TestNewServer(t *testing.T) {
 				return
 			}
 
-			_, err = createNewGopassClient(tt.args.ctx, dir)
+			_, err = createNewGopassClient(tt.args.ctx, dir, t.TempDir(), nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createNewGopassClient() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,7 +95,7 @@ func TestInitializeRepository(t *testing.T) {
 	}
 
 	deleteDirectory(t, repoDir)
-	deleteDirectory(t, r.Repositories[repoDir].directory)
+	r.Repositories[repoDir].remove()
 }
 
 func TestCloneRepository(t *testing.T) {
@@ -103,7 +104,7 @@ func TestCloneRepository(t *testing.T) {
 
 	unzip(filepath.Join("resources_test", "password-store.zip"), repoDir, t)
 
-	_, err := cloneGopassRepo(repoDir, targetDir, "", "")
+	_, err := cloneGopassRepo(repoDir, "", targetDir, cluster.Credentials{})
 	if err != nil {
 		t.Errorf("not able to clone repository: %v", err)
 		return
@@ -113,13 +114,207 @@ func TestCloneRepository(t *testing.T) {
 func TestInitializeNewGopassRepository(t *testing.T) {
 	repoDir := initializeTestRepository(t)
 
-	repository, err := initializeNewGopassRepository(repoDir, cluster.Secret{})
+	homeDirectory, err := createHomeDirectory()
+	if err != nil {
+		t.Errorf("not able to create home directory: %v\n", err)
+		return
+	}
+
+	repository, err := initializeNewGopassRepository(repoDir, "", "", cluster.Credentials{}, homeDirectory, nil)
+	if err != nil {
+		t.Errorf("not able to initialize gopass repository: %v\n", err)
+		return
+	}
+
+	configFile := filepath.Join(homeDirectory, "config.yml")
+	if _, err := os.Stat(configFile); err != nil {
+		t.Errorf("configuration file '%s' not found: %v", configFile, err)
+	}
+
+	repository.remove()
+
+	for _, directory := range []string{repository.directory, repository.homeDirectory} {
+		if _, err := os.Stat(directory); !os.IsNotExist(err) {
+			t.Errorf("directory '%s' has not been removed", directory)
+		}
+	}
+}
+
+func TestStoreDirectory(t *testing.T) {
+	repoDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(repoDir, "secrets", "team"), 0700)
+	if err != nil {
+		t.Errorf("not able to create store directory: %v", err)
+		return
+	}
+	err = ioutil.WriteFile(filepath.Join(repoDir, "secrets", ".gpg-id"), []byte("someone@example.com"), 0600)
+	if err != nil {
+		t.Errorf("not able to write .gpg-id: %v", err)
+		return
+	}
+
+	tests := []struct {
+		name      string
+		storePath string
+		wanted    string
+		wantErr   bool
+	}{
+		{name: "without store path", storePath: "", wanted: repoDir},
+		{name: "subdirectory", storePath: "secrets", wanted: filepath.Join(repoDir, "secrets")},
+		{name: "subdirectory with trailing slash", storePath: "secrets/", wanted: filepath.Join(repoDir, "secrets")},
+		{name: "subdirectory without .gpg-id", storePath: "secrets/team", wantErr: true},
+		{name: "missing subdirectory", storePath: "missing", wantErr: true},
+		{name: "outside of the repository", storePath: "../secrets", wantErr: true},
+		{name: "absolute path", storePath: filepath.Join(repoDir, "secrets"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storeDirectory(repoDir, tt.storePath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("storeDirectory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.wanted {
+				t.Errorf("storeDirectory() = %s, wanted %s", got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestInitializeNewGopassRepositoryWithStorePath(t *testing.T) {
+	repoDir, repository, err := initializeLocalRepository(t)
+	if err != nil {
+		return
+	}
+	commitFiles(t, repository, repoDir, map[string]string{
+		"README.md":              "documentation next to the store",
+		"secrets/.gpg-id":        "someone@example.com",
+		"secrets/database.gpg":   "database",
+		"deployment/values.yaml": "replicas: 1",
+	})
+
+	homeDirectory := t.TempDir()
+	gopassRepository, err := initializeNewGopassRepository(repoDir, "", "secrets/", cluster.Credentials{}, homeDirectory, nil)
 	if err != nil {
 		t.Errorf("not able to initialize gopass repository: %v\n", err)
 		return
 	}
+	defer removeDirectory(gopassRepository.directory)
+
+	configuration, err := ioutil.ReadFile(filepath.Join(homeDirectory, "config.yml"))
+	if err != nil {
+		t.Errorf("not able to read configuration file: %v", err)
+		return
+	}
+	if !strings.Contains(string(configuration), "path: "+filepath.Join(gopassRepository.directory, "secrets")+"\n") {
+		t.Errorf("configuration '%s' does not point to the store path", configuration)
+	}
+	if gopassRepository.storePath != "secrets" {
+		t.Errorf("store path is '%s', wanted 'secrets'", gopassRepository.storePath)
+	}
 
-	deleteDirectory(t, repository.directory)
+	_, err = initializeNewGopassRepository(repoDir, "", "deployment", cluster.Credentials{}, t.TempDir(), nil)
+	if err == nil {
+		t.Errorf("initialized repository with a store path not containing a .gpg-id")
+	}
+}
+
+func TestCreateHomeDirectory(t *testing.T) {
+	homeDirectory, err := createHomeDirectory()
+	if err != nil {
+		t.Errorf("not able to create home directory: %v", err)
+		return
+	}
+	defer deleteDirectory(t, homeDirectory)
+
+	info, err := os.Stat(gnupgHome(homeDirectory))
+	if err != nil {
+		t.Errorf("GnuPG home not found: %v", err)
+		return
+	}
+
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("GnuPG home has permissions %o, wanted %o", info.Mode().Perm(), 0700)
+	}
+
+	otherHomeDirectory, err := createHomeDirectory()
+	if err != nil {
+		t.Errorf("not able to create home directory: %v", err)
+		return
+	}
+	defer deleteDirectory(t, otherHomeDirectory)
+
+	if gnupgHome(homeDirectory) == gnupgHome(otherHomeDirectory) {
+		t.Errorf("repositories share the GnuPG home '%s'", gnupgHome(homeDirectory))
+	}
+}
+
+// gpgAgentTestClient starts a gpg-agent in the GnuPG home like importing the GPG key does. The agent does not shut
+// down when its socket is removed, like agents with their socket in /run/user, so it keeps running unless it is stopped.
+type gpgAgentTestClient struct {
+	cluster.KubernetesTestClient
+	gnupgHome string
+}
+
+func (c *gpgAgentTestClient) GetGpgKey(_ context.Context, _ string, gnupgHome string, _ *gopass_repository.GpgKeyReference) error {
+	c.gnupgHome = gnupgHome
+	err := ioutil.WriteFile(filepath.Join(gnupgHome, "gpg-agent.conf"), []byte("disable-check-own-socket\n"), 0600)
+	if err != nil {
+		return err
+	}
+	return exec.Command("gpg-connect-agent", "--homedir", gnupgHome, "/bye").Run()
+}
+
+// gpgAgentRunning reports whether a gpg-agent serving the GnuPG home is running.
+func gpgAgentRunning(t *testing.T, gnupgHome string) bool {
+	processes, err := filepath.Glob("/proc/[0-9]*/cmdline")
+	if err != nil {
+		t.Fatalf("unable to list processes: %v", err)
+	}
+	for _, process := range processes {
+		commandLine, err := ioutil.ReadFile(process)
+		if err != nil {
+			continue
+		}
+		arguments := strings.Split(string(commandLine), "\x00")
+		if strings.HasSuffix(arguments[0], "gpg-agent") && strings.Contains(string(commandLine), "\x00"+gnupgHome+"\x00") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInitializeRepositoryStopsGpgAgentOnFailure(t *testing.T) {
+	client := &gpgAgentTestClient{}
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       client,
+	}
+
+	_, err := r.initializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			RepositoryURL:  filepath.Join(os.TempDir(), "gopass-does-not-exist"),
+			Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		},
+	})
+	if err == nil {
+		t.Errorf("initialized repository that does not exist")
+		return
+	}
+	if client.gnupgHome == "" {
+		t.Errorf("GPG key has not been fetched")
+		return
+	}
+
+	for i := 0; i < 50 && gpgAgentRunning(t, client.gnupgHome); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if gpgAgentRunning(t, client.gnupgHome) {
+		t.Errorf("gpg-agent of GnuPG home '%s' is still running", client.gnupgHome)
+	}
+	if _, err := os.Stat(filepath.Dir(client.gnupgHome)); !os.IsNotExist(err) {
+		t.Errorf("home directory '%s' has not been removed", filepath.Dir(client.gnupgHome))
+	}
 }
 
 func TestCloneAndUpdateRepository(t *testing.T) {
@@ -127,7 +322,7 @@ func TestCloneAndUpdateRepository(t *testing.T) {
 	unzip(filepath.Join("resources_test", "password-store.zip"), localRepoDir, t)
 
 	targetDir := t.TempDir()
-	repository, err := cloneGopassRepo(localRepoDir, targetDir, "", "")
+	repository, err := cloneGopassRepo(localRepoDir, "", targetDir, cluster.Credentials{})
 	if err != nil {
 		t.Errorf("unable to clone repository: %v\n", err)
 	}
@@ -153,7 +348,7 @@ func TestCloneAndUpdateRepository(t *testing.T) {
 		t.Errorf("unable to add file: %v", err)
 	}
 
-	_, err = worktree.Commit("some commit", &git.CommitOptions{
+	commit, err := worktree.Commit("some commit", &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  "John Doe",
 			Email: "john@doe.org",
@@ -162,11 +357,15 @@ func TestCloneAndUpdateRepository(t *testing.T) {
 	})
 
 	r, repo := createRepositoryServer(repository)
-	err = r.updateRepository(context.Background(), &repo)
+	commitHash, err := r.updateRepository(context.Background(), &repo)
 	if err != nil {
 		t.Errorf("unable to update repository: %v\n", err)
 	}
 
+	if commitHash != commit.String() {
+		t.Errorf("received commit hash '%s', expected '%s'", commitHash, commit.String())
+	}
+
 }
 
 func TestUpdateGopassRepository(t *testing.T) {
@@ -229,14 +428,14 @@ func TestUpdateGopassRepository(t *testing.T) {
 	deleteDirectory(t, localRepoDir)
 }
 
-func createRepositoryServer(init *git.Repository) (RepositoryServer, gopass_repository.Repository) {
+func createRepositoryServer(init *git.Repository) (*RepositoryServer, gopass_repository.Repository) {
 	gr := gopassRepo{
 		store:      nil,
 		directory:  "",
 		repository: init,
 	}
 
-	r := RepositoryServer{
+	r := &RepositoryServer{
 		Repositories: map[string]*gopassRepo{
 			"myRepo": &gr,
 		},
@@ -279,7 +478,7 @@ func initializeTestRepository(t *testing.T) string {
 	return filepath.Join(repoDir, ".password-store")
 }
 
-func unzip(src string, dest string, t *testing.T) {
+func unzip(src string, dest string, t testing.TB) {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		t.Errorf("not able to open repository: %v", err)
@@ -0,0 +1,102 @@
+package gopass_repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+const defaultRegistryUsernameField = "username"
+
+// requiredKeys lists the keys a Secret of the given type has to contain. For basic-auth one of the keys is sufficient.
+var requiredKeys = map[corev1.SecretType][]string{
+	corev1.SecretTypeOpaque:           {},
+	corev1.SecretTypeDockerConfigJson: {corev1.DockerConfigJsonKey},
+	corev1.SecretTypeTLS:              {corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+	corev1.SecretTypeBasicAuth:        {corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey},
+	corev1.SecretTypeSSHAuth:          {corev1.SSHAuthPrivateKey},
+}
+
+func toSecretType(secretType string) (corev1.SecretType, error) {
+	if secretType == "" {
+		return corev1.SecretTypeOpaque, nil
+	}
+
+	if _, ok := requiredKeys[corev1.SecretType(secretType)]; !ok {
+		return "", fmt.Errorf("unknown secret type '%s'", secretType)
+	}
+	return corev1.SecretType(secretType), nil
+}
+
+// validateSecretType checks that the data of a Secret contains the keys required by its type.
+func validateSecretType(name types.NamespacedName, secretType corev1.SecretType, secretMap map[string]string) error {
+	keys := requiredKeys[secretType]
+
+	missing := make([]string, 0)
+	for _, key := range keys {
+		if _, ok := secretMap[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if secretType == corev1.SecretTypeBasicAuth {
+		if len(missing) == len(keys) {
+			return fmt.Errorf("secret '%s' of type '%s' requires key '%s' or '%s'", name, secretType, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+		}
+		return nil
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("secret '%s' of type '%s' is missing the keys '%s'", name, secretType, strings.Join(missing, "', '"))
+	}
+	return nil
+}
+
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// createDockerConfigJson builds the content of a .dockerconfigjson from the registry credentials stored in entries.
+// The password of an entry is used as password, the username is read from a field of its body.
+func createDockerConfigJson(name types.NamespacedName, registries []*gopass_repository.RegistryCredentials, passwordsByName map[string]cluster.Secret) (string, error) {
+	config := dockerConfig{Auths: make(map[string]dockerConfigEntry)}
+
+	for _, registry := range registries {
+		password, ok := passwordsByName[registry.Path]
+		if !ok {
+			return "", fmt.Errorf("entry '%s' of registry '%s' of secret '%s' not found", registry.Path, registry.Server, name)
+		}
+
+		usernameField := registry.UsernameField
+		if usernameField == "" {
+			usernameField = defaultRegistryUsernameField
+		}
+		username, ok := password.Fields[usernameField]
+		if !ok {
+			return "", fmt.Errorf("field '%s' of entry '%s' of registry '%s' of secret '%s' not found", usernameField, registry.Path, registry.Server, name)
+		}
+
+		config.Auths[registry.Server] = dockerConfigEntry{
+			Username: username,
+			Password: password.Password,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password.Password)),
+		}
+	}
+
+	dockerConfigJson, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(dockerConfigJson), nil
+}
@@ -0,0 +1,177 @@
+package gopass_repository
+
+import (
+	"context"
+	"github.com/gopasspw/gopass/pkg/gopass/apimock"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func TestValidateSecretType(t *testing.T) {
+	name := types.NamespacedName{Namespace: "testNamespace", Name: "target"}
+
+	tests := []struct {
+		name       string
+		secretType corev1.SecretType
+		secretMap  map[string]string
+		wantErr    bool
+	}{
+		{name: "opaque accepts any keys", secretType: corev1.SecretTypeOpaque, secretMap: map[string]string{}},
+		{name: "complete tls", secretType: corev1.SecretTypeTLS, secretMap: map[string]string{"tls.crt": "cert", "tls.key": "key"}},
+		{name: "tls without key", secretType: corev1.SecretTypeTLS, secretMap: map[string]string{"tls.crt": "cert"}, wantErr: true},
+		{name: "basic-auth with password only", secretType: corev1.SecretTypeBasicAuth, secretMap: map[string]string{"password": "secret"}},
+		{name: "basic-auth without credentials", secretType: corev1.SecretTypeBasicAuth, secretMap: map[string]string{"other": "value"}, wantErr: true},
+		{name: "ssh-auth", secretType: corev1.SecretTypeSSHAuth, secretMap: map[string]string{"ssh-privatekey": "key"}},
+		{name: "dockerconfigjson without config", secretType: corev1.SecretTypeDockerConfigJson, secretMap: map[string]string{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSecretType(name, tt.secretType, tt.secretMap)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSecretType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateSecretTargetsWithInvalidType(t *testing.T) {
+	tests := []struct {
+		name   string
+		target *gopass_repository.SecretTarget
+	}{
+		{
+			name:   "unknown type",
+			target: &gopass_repository.SecretTarget{Type: "example.com/unknown"},
+		},
+		{
+			name: "registries without dockerconfigjson type",
+			target: &gopass_repository.SecretTarget{
+				Registries: []*gopass_repository.RegistryCredentials{{Server: "registry.example.com", Path: "registry"}},
+			},
+		},
+		{
+			name: "registry without path",
+			target: &gopass_repository.SecretTarget{
+				Type:       string(corev1.SecretTypeDockerConfigJson),
+				Registries: []*gopass_repository.RegistryCredentials{{Server: "registry.example.com"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.target.Name = &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"}
+			_, err := createSecretTargets(&gopass_repository.Repository{
+				Secrets: []*gopass_repository.SecretTarget{tt.target},
+			})
+			if err == nil {
+				t.Errorf("createSecretTargets() did not return an error")
+			}
+		})
+	}
+}
+
+func TestSecretTarget_createSecretMapWithDockerConfigJson(t *testing.T) {
+	targets, err := createSecretTargets(&gopass_repository.Repository{
+		Secrets: []*gopass_repository.SecretTarget{
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "registry"},
+				Type: string(corev1.SecretTypeDockerConfigJson),
+				Registries: []*gopass_repository.RegistryCredentials{
+					{Server: "registry.example.com", Path: "team-a/registry"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("createSecretTargets() error = %v", err)
+		return
+	}
+
+	if !targets[0].wants("team-a/registry") {
+		t.Errorf("entry of registry is not wanted by the target")
+	}
+
+	passwords := []cluster.Secret{
+		{Name: "team-a/registry", Password: "secret", Fields: map[string]string{"username": "robot"}},
+	}
+	content, err := targets[0].createSecretMap(passwords, nil)
+	if err != nil {
+		t.Errorf("createSecretMap() error = %v", err)
+		return
+	}
+
+	wanted := map[string]string{
+		".dockerconfigjson": `{"auths":{"registry.example.com":{"username":"robot","password":"secret","auth":"cm9ib3Q6c2VjcmV0"}}}`,
+	}
+	if !reflect.DeepEqual(content.data, wanted) {
+		t.Errorf("createSecretMap() = %v, wanted %v", content.data, wanted)
+	}
+}
+
+func TestUpdateAllPasswordsRecreatesSecretWithChangedType(t *testing.T) {
+	store := apimock.New()
+	for name, password := range map[string]string{
+		"tls/cert": "certificate",
+		"tls/key":  "private key",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	kubernetesClient := newApplyClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "tls"},
+		Type:       corev1.SecretTypeOpaque,
+	})
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	_, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		Secrets: []*gopass_repository.SecretTarget{
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "tls"},
+				Type: string(corev1.SecretTypeTLS),
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "tls.crt", Path: "tls/cert"},
+					{Key: "tls.key", Path: "tls/key"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+
+	secret, err := kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "tls", metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("unable to get secret: %v", err)
+		return
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		t.Errorf("secret has type '%s', wanted '%s'", secret.Type, corev1.SecretTypeTLS)
+	}
+
+	deleted := false
+	for _, action := range kubernetesClient.Actions() {
+		if action.GetVerb() == "delete" {
+			deleted = true
+		}
+	}
+	if !deleted {
+		t.Errorf("secret with changed type was not recreated")
+	}
+}
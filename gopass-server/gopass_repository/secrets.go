@@ -3,6 +3,7 @@ package gopass_repository
 import (
 	"context"
 	"fmt"
+	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
 	corev1 "k8s.io/api/core/v1"
@@ -11,45 +12,150 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"log"
-	"regexp"
+	"strings"
 )
 
-func (r *RepositoryServer) updateAllPasswords(ctx context.Context, repository *gopass_repository.Repository) error {
-	repo, ok := (r.Repositories)[(*repository).RepositoryURL]
+type syncResult struct {
+	commitHash     string
+	syncedEntries  int
+	collisions     []*gopass_repository.KeyCollision
+	templateErrors []*gopass_repository.TemplateError
+	staleKeys      []*gopass_repository.StaleKey
+	updated        []*gopass_repository.NamespacedName
+	unchanged      []*gopass_repository.NamespacedName
+	untargeted     []*gopass_repository.NamespacedName
+}
+
+func (r *RepositoryServer) updateAllPasswords(ctx context.Context, repository *gopass_repository.Repository) (syncResult, error) {
+	repo, ok := r.getRepository((*repository).RepositoryURL)
 	if !ok {
-		return fmt.Errorf("repository with URL '%s' not found", (*repository).RepositoryURL)
+		return syncResult{}, fmt.Errorf("repository with URL '%s' not found", (*repository).RepositoryURL)
 	}
 
-	passwords, err := fetchAllPasswords(ctx, repo)
+	// entries are read from the worktree, which must not be changed by a concurrent pull
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+
+	commitHash, err := headCommit(repo.repository)
 	if err != nil {
-		log.Printf("error fetching passwords: %v\n", err)
-		return err
+		return syncResult{}, err
+	}
+	err = repo.refreshEntryCache(commitHash)
+	if err != nil {
+		return syncResult{}, err
 	}
 
-	secretList := gopass_repository.SecretList{
-		Secrets: make([]*gopass_repository.Secret, 0),
+	filter, err := newEntryFilter(repository.Include, repository.Exclude)
+	if err != nil {
+		log.Printf("invalid filter: %v\n", err)
+		return syncResult{}, err
+	}
+
+	err = validatePrunePolicy(repository.PrunePolicy)
+	if err != nil {
+		log.Printf("invalid prune policy: %v\n", err)
+		return syncResult{}, err
 	}
 
-	for _, password := range passwords {
-		secretList.Secrets = append(secretList.Secrets, &gopass_repository.Secret{
-			Name:     password.Name,
-			Password: password.Password,
-		})
+	targets, err := createSecretTargets(repository)
+	if err != nil {
+		log.Printf("invalid secret targets: %v\n", err)
+		return syncResult{}, err
+	}
+
+	passwords, err := fetchAllPasswords(ctx, repo, func(name string) bool {
+		return filter.matches(name) && wantedByAny(targets, name)
+	})
+	if err != nil {
+		log.Printf("error fetching passwords: %v\n", err)
+		return syncResult{}, err
+	}
+
+	lookup := newEntryLookup(ctx, repo, filter, passwords)
+
+	collisions := make([]*gopass_repository.KeyCollision, 0)
+	templateErrors := make([]*gopass_repository.TemplateError, 0)
+	staleKeys := make([]*gopass_repository.StaleKey, 0)
+	updated := make([]*gopass_repository.NamespacedName, 0)
+	unchangedTargets := make([]*gopass_repository.NamespacedName, 0)
+	failures := make([]error, 0)
+	for _, target := range targets {
+		content, err := target.createSecretMap(passwords, lookup)
+		collisions = append(collisions, content.collisions...)
+		templateErrors = append(templateErrors, content.templateErrors...)
+		if err != nil {
+			log.Printf("unable to create secret map: %v\n", err)
+			failures = append(failures, err)
+			continue
+		}
+
+		if repository.PrunePolicy == prunePolicyKeep {
+			targetStaleKeys, err := r.keepStaleKeys(ctx, target, content.data)
+			if err != nil {
+				failures = append(failures, err)
+				continue
+			}
+			staleKeys = append(staleKeys, targetStaleKeys...)
+		}
+
+		var written bool
+		if target.kind == targetKindConfigMap {
+			written, err = r.updateConfigMap(ctx, target, content.data)
+		} else {
+			written, err = r.updateSecretMap(ctx, target, content.data)
+		}
+		if err != nil {
+			log.Printf("unable to update secret map: %v\n", err)
+			failures = append(failures, err)
+			continue
+		}
+
+		name := &gopass_repository.NamespacedName{Namespace: target.name.Namespace, Name: target.name.Name}
+		if written {
+			updated = append(updated, name)
+		} else {
+			unchangedTargets = append(unchangedTargets, name)
+		}
 	}
 
-	err = r.updateSecretMap(ctx, types.NamespacedName{
-		Namespace: repository.SecretName.Namespace,
-		Name:      repository.SecretName.Name,
-	}, &secretList)
+	untargeted, err := r.pruneRemovedTargets(ctx, repository, targets)
 	if err != nil {
-		log.Printf("unable to update secret map: %v\n", err)
-		return err
+		log.Printf("unable to prune removed targets: %v\n", err)
+		failures = append(failures, err)
 	}
 
-	return nil
+	return syncResult{
+		commitHash:     commitHash,
+		syncedEntries:  len(passwords),
+		collisions:     collisions,
+		templateErrors: templateErrors,
+		staleKeys:      staleKeys,
+		updated:        updated,
+		unchanged:      unchangedTargets,
+		untargeted:     untargeted,
+	}, combinedError(failures)
 }
 
-func fetchAllPasswords(ctx context.Context, repo *gopassRepo) ([]cluster.Secret, error) {
+// combinedError returns an error describing all failures of a sync, or nil if there are none. Failing targets do not
+// keep the remaining targets from being synced.
+func combinedError(failures []error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	if len(failures) == 1 {
+		return failures[0]
+	}
+
+	descriptions := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		descriptions = append(descriptions, failure.Error())
+	}
+	return fmt.Errorf("%d errors during sync: %s", len(failures), strings.Join(descriptions, "; "))
+}
+
+// fetchAllPasswords returns all entries accepted by wanted. Other entries are never decrypted.
+// Entries not changed since they have last been decrypted are taken from the cache.
+func fetchAllPasswords(ctx context.Context, repo *gopassRepo, wanted func(name string) bool) ([]cluster.Secret, error) {
 	list, err := (*repo).store.List(ctx)
 	if err != nil {
 		log.Printf("not able to list contents of repository: %v\n", err)
@@ -59,49 +165,86 @@ func fetchAllPasswords(ctx context.Context, repo *gopassRepo) ([]cluster.Secret,
 	passwords := make([]cluster.Secret, 0)
 
 	for _, passwordName := range list {
-		password, err := (*repo).store.Get(ctx, passwordName, "")
+		if !wanted(passwordName) {
+			continue
+		}
+
+		password, err := decryptEntry(ctx, repo, passwordName)
 		if err != nil {
 			log.Printf("not able to fetch password '%s': %v\n", passwordName, err)
 			continue
 		}
-		passwords = append(passwords, cluster.Secret{
-			Name:     passwordName,
-			Password: password.Password(),
-		})
+		passwords = append(passwords, password)
 	}
 
 	return passwords, nil
 }
 
-func (r *RepositoryServer) updateSecretMap(ctx context.Context, namespacedName types.NamespacedName, secrets *gopass_repository.SecretList) error {
-	log.Printf("updating secret map\n")
+// secretFields returns the key-value pairs of the body of an entry, e.g. 'username: admin'.
+func secretFields(secret gopass.Secret) map[string]string {
+	fields := make(map[string]string)
+	for _, key := range secret.Keys() {
+		if key == "password" {
+			continue
+		}
+		if value, ok := secret.Get(key); ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
 
-	newSecret := createSecret(secrets, namespacedName)
+// updateSecretMap writes the Secret unless it already contains the data. It reports whether it was written.
+// As the type of a Secret is immutable, a Secret of another type is recreated.
+func (r *RepositoryServer) updateSecretMap(ctx context.Context, target secretTarget, secretMap map[string]string) (bool, error) {
+	hash := contentHash(target, secretMap)
 
-	_, err := getSecretMap(ctx, r.KubernetesClient, namespacedName)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			log.Printf("creating secret map")
+	existingSecret, err := getSecretMap(ctx, r.KubernetesClient, target.name)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Printf("unable to fetch secret map: %v\n", err)
+		return false, err
+	}
 
-			_, err := r.KubernetesClient.CoreV1().Secrets(namespacedName.Namespace).Create(ctx, &newSecret, metav1.CreateOptions{})
-			if err != nil {
-				log.Printf("unable to create secret map: %v\n", err)
-				return err
-			}
-			return nil
-		} else {
-			log.Printf("unable to fetch secret map: %v\n", err)
-			return err
+	if err == nil && sameSecretType(existingSecret.Type, target.secretType) && unchanged(existingSecret.ObjectMeta, secretData(existingSecret), target, hash, secretMap) {
+		log.Printf("secret map '%s' is unchanged\n", target.name)
+		return false, nil
+	}
+
+	log.Printf("updating secret map '%s'\n", target.name)
+	if err == nil && !sameSecretType(existingSecret.Type, target.secretType) {
+		log.Printf("type of secret map changed from '%s' to '%s', recreating it", existingSecret.Type, target.secretType)
+
+		err = r.KubernetesClient.CoreV1().Secrets(target.name.Namespace).Delete(ctx, target.name.Name, metav1.DeleteOptions{})
+		if err != nil {
+			log.Printf("unable to delete secret map: %v\n", err)
+			return false, err
+		}
+	} else if err == nil {
+		err = r.handOverSecret(ctx, existingSecret)
+		if err != nil {
+			log.Printf("unable to hand over fields of secret map: %v\n", err)
+			return false, err
 		}
 	}
 
-	_, err = r.KubernetesClient.CoreV1().Secrets(namespacedName.Namespace).Update(ctx, &newSecret, metav1.UpdateOptions{})
+	err = r.applySecret(ctx, target, secretMap, hash)
 	if err != nil {
-		log.Printf("not able to update secret map: %v", err)
-		return err
+		log.Printf("not able to apply secret map: %v", err)
+		return false, err
 	}
 
-	return nil
+	return true, nil
+}
+
+// sameSecretType compares types of Secrets, treating an unset type as Opaque.
+func sameSecretType(existing corev1.SecretType, wanted corev1.SecretType) bool {
+	if existing == "" {
+		existing = corev1.SecretTypeOpaque
+	}
+	if wanted == "" {
+		wanted = corev1.SecretTypeOpaque
+	}
+	return existing == wanted
 }
 
 func getSecretMap(ctx context.Context, clientset kubernetes.Interface, namespacedName types.NamespacedName) (*corev1.Secret, error) {
@@ -113,33 +256,6 @@ func getSecretMap(ctx context.Context, clientset kubernetes.Interface, namespace
 	return secretMap, nil
 }
 
-func createSecret(secrets *gopass_repository.SecretList, namespacedName types.NamespacedName) corev1.Secret {
-	newSecretMap := createSecretMap(secrets)
-
-	newSecret := corev1.Secret{
-		TypeMeta: metav1.TypeMeta{},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		StringData: newSecretMap,
-	}
-	return newSecret
-}
-
-func createSecretMap(secrets *gopass_repository.SecretList) map[string]string {
-	newSecretMap := make(map[string]string)
-	for _, secret := range secrets.Secrets {
-		newSecretMap[rename(secret.Name)] = secret.Password
-	}
-	return newSecretMap
-}
-
-func rename(name string) string {
-	reg, _ := regexp.Compile("[^a-zA-Z0-9]+")
-	return reg.ReplaceAllString(name, "-")
-}
-
 func (r *RepositoryServer) deleteSecretMap(ctx context.Context, namespacedName types.NamespacedName) (bool, error) {
 	log.Printf("deleting secret")
 
@@ -2,6 +2,7 @@ package gopass_repository
 
 import (
 	"context"
+	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/apimock"
 	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
@@ -19,7 +20,7 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 	type fields struct {
 		Repositories     map[string]*gopassRepo
 		Client           cluster.Client
-		KubernetesClient *fake.Clientset
+		KubernetesClient *applyClientset
 	}
 	type args struct {
 		ctx        context.Context
@@ -30,8 +31,9 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 		fields        fields
 		args          args
 		wantErr       bool
-		wantedActions []clientgotesting.Action
+		wantedData    map[string]string
 		passwords     map[string]string
+		wantedEntries int32
 	}{
 		{
 			name: "Secret map does not exist yet. Creating it.",
@@ -44,7 +46,7 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 					},
 				},
 				Client:           &cluster.KubernetesTestClient{},
-				KubernetesClient: fake.NewSimpleClientset(),
+				KubernetesClient: newApplyClientset(),
 			},
 			args: args{
 				ctx: nil,
@@ -57,16 +59,8 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
-			wantedActions: []clientgotesting.Action{
-				clientgotesting.NewCreateAction(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, "testNamespace", &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "someSecret",
-						Namespace: "testNamespace",
-					},
-					StringData: make(map[string]string),
-				}),
-			},
+			wantErr:    false,
+			wantedData: map[string]string{},
 		},
 		{
 			name: "Update existing Secret map.",
@@ -79,7 +73,7 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 					},
 				},
 				Client: &cluster.KubernetesTestClient{},
-				KubernetesClient: fake.NewSimpleClientset(&corev1.Secret{
+				KubernetesClient: newApplyClientset(&corev1.Secret{
 					TypeMeta: metav1.TypeMeta{},
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "testNamespace",
@@ -98,17 +92,10 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
-			wantedActions: []clientgotesting.Action{
-				clientgotesting.NewUpdateAction(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, "testNamespace", &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "someSecret",
-						Namespace: "testNamespace",
-					},
-					StringData: map[string]string{"secretKey": "secretSecret"},
-				}),
-			},
-			passwords: map[string]string{"secretKey": "secretSecret"},
+			wantErr:       false,
+			wantedData:    map[string]string{"secretKey": "secretSecret"},
+			passwords:     map[string]string{"secretKey": "secretSecret"},
+			wantedEntries: 1,
 		},
 	}
 	for _, tt := range tests {
@@ -144,16 +131,96 @@ func TestRepositoryServer_updateAllPasswords(t *testing.T) {
 				t.Errorf("received error message '%s', expected empty error message", response.ErrorMessage)
 			}
 
-			actions := tt.fields.KubernetesClient.Actions()
-			for _, wantedAction := range tt.wantedActions {
-				if !containsAction(actions, wantedAction) {
-					t.Errorf("List of actions does not contain: %v\n contained: %v", wantedAction, actions)
-				}
+			if err == nil && response.SyncedEntries != tt.wantedEntries {
+				t.Errorf("received %d synced entries, expected %d", response.SyncedEntries, tt.wantedEntries)
+			}
+
+			secret, err := tt.fields.KubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "someSecret", metav1.GetOptions{})
+			if err != nil {
+				t.Errorf("unable to get secret: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(secretData(secret), tt.wantedData) {
+				t.Errorf("secret contains %v, wanted %v", secretData(secret), tt.wantedData)
 			}
 		})
 	}
 }
 
+// recordingStore records the names of all entries that have been decrypted.
+type recordingStore struct {
+	*apimock.MockAPI
+	fetched []string
+}
+
+func (r *recordingStore) Get(ctx context.Context, name, revision string) (gopass.Secret, error) {
+	r.fetched = append(r.fetched, name)
+	return r.MockAPI.Get(ctx, name, revision)
+}
+
+func TestFetchAllPasswordsOnlyDecryptsFilteredEntries(t *testing.T) {
+	store := &recordingStore{MockAPI: apimock.New()}
+	for _, name := range []string{"team-a/prod/database", "team-a/prod/personal/bank", "team-b/token"} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte("password of " + name)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	filter, err := newEntryFilter([]string{"team-a/**"}, []string{"**/personal/**"})
+	if err != nil {
+		t.Errorf("unable to create filter: %v", err)
+		return
+	}
+
+	passwords, err := fetchAllPasswords(context.Background(), &gopassRepo{store: store}, filter.matches)
+	if err != nil {
+		t.Errorf("fetchAllPasswords() error = %v", err)
+		return
+	}
+
+	wantedPasswords := []cluster.Secret{
+		{Name: "team-a/prod/database", Password: "password of team-a/prod/database", Fields: map[string]string{}},
+	}
+	if !reflect.DeepEqual(passwords, wantedPasswords) {
+		t.Errorf("fetchAllPasswords() = %v, wanted %v", passwords, wantedPasswords)
+	}
+
+	if !reflect.DeepEqual(store.fetched, []string{"team-a/prod/database"}) {
+		t.Errorf("decrypted entries %v, wanted only entries matching the filter", store.fetched)
+	}
+}
+
+func TestFetchAllPasswordsWithFields(t *testing.T) {
+	store := apimock.New()
+	err := store.Set(context.Background(), "db-prod", &apimock.Secret{Buf: []byte("my password\nusername: admin\nurl: postgres://db:5432\n")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+
+	passwords, err := fetchAllPasswords(context.Background(), &gopassRepo{store: store}, func(string) bool { return true })
+	if err != nil {
+		t.Errorf("fetchAllPasswords() error = %v", err)
+		return
+	}
+
+	wantedPasswords := []cluster.Secret{
+		{
+			Name:     "db-prod",
+			Password: "my password",
+			Fields: map[string]string{
+				"username": "admin",
+				"url":      "postgres://db:5432",
+			},
+		},
+	}
+	if !reflect.DeepEqual(passwords, wantedPasswords) {
+		t.Errorf("fetchAllPasswords() = %v, wanted %v", passwords, wantedPasswords)
+	}
+}
+
 func TestRepositoryServer_deleteSecretMap(t *testing.T) {
 	type fields struct {
 		Repositories     map[string]*gopassRepo
@@ -7,22 +7,105 @@ import (
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
 	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
-	"k8s.io/apimachinery/pkg/types"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"log"
+	"os"
+	"strings"
+	"sync"
 )
 
 type RepositoryServer struct {
 	Repositories     map[string]*gopassRepo
 	Client           cluster.Client
 	KubernetesClient kubernetes.Interface
+	// LegacyFieldManagers have written Secrets and ConfigMaps with Update before server-side apply was used. Their
+	// fields are handed over to the operator. Defaults to the user agent of the repository server.
+	LegacyFieldManagers []string
+	// repositoriesLock guards Repositories, as RPCs are handled concurrently
+	repositoriesLock sync.RWMutex
 }
 
 type gopassRepo struct {
-	store      gopass.Store
-	directory  string
-	repository *git.Repository
+	// lock serializes pulls and reads of the worktree as well as the access to the cache
+	lock          sync.Mutex
+	store         gopass.Store
+	directory     string
+	homeDirectory string
+	storePath     string
+	ref           string
+	repository    *git.Repository
+	mounts        []*mountedRepo
+	fingerprint   string
+	cache         *entryCache
+}
+
+// remove deletes the clone of the repository and its mounts as well as its gopass configuration and GnuPG home.
+// The gpg-agent of the GnuPG home is stopped, so no key material is kept in memory.
+func (g *gopassRepo) remove() {
+	removeDirectory(g.directory)
+	removeMounts(g.mounts)
+	if g.homeDirectory != "" {
+		removeHomeDirectory(g.homeDirectory)
+	}
+}
+
+// getRepository returns the initialized repository with the given URL.
+func (r *RepositoryServer) getRepository(url string) (*gopassRepo, bool) {
+	r.repositoriesLock.RLock()
+	defer r.repositoriesLock.RUnlock()
+
+	repo, ok := r.Repositories[url]
+	return repo, ok
+}
+
+// replaceRepository registers the repository in place of the previous one, which is nil if there was none. If another
+// repository with the same URL has been registered in the meantime, it is kept. It returns the registered repository.
+func (r *RepositoryServer) replaceRepository(url string, previous *gopassRepo, repo *gopassRepo) *gopassRepo {
+	r.repositoriesLock.Lock()
+	defer r.repositoriesLock.Unlock()
+
+	if existingRepository := r.Repositories[url]; existingRepository != previous {
+		return existingRepository
+	}
+	r.Repositories[url] = repo
+	return repo
+}
+
+// unregisterRepository unregisters the repository if it is still registered with the given URL. It reports whether it
+// was unregistered.
+func (r *RepositoryServer) unregisterRepository(url string, repo *gopassRepo) bool {
+	r.repositoriesLock.Lock()
+	defer r.repositoriesLock.Unlock()
+
+	if r.Repositories[url] != repo {
+		return false
+	}
+	delete(r.Repositories, url)
+	return true
+}
+
+// removeRepository unregisters the repository with the given URL. It reports whether the repository was registered.
+func (r *RepositoryServer) removeRepository(url string) (*gopassRepo, bool) {
+	r.repositoriesLock.Lock()
+	defer r.repositoriesLock.Unlock()
+
+	repo, ok := r.Repositories[url]
+	delete(r.Repositories, url)
+	return repo, ok
+}
+
+// headCommit returns the commit HEAD of the repository points to.
+func (g *gopassRepo) headCommit() (string, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return headCommit(g.repository)
 }
 
 func Initialize() (*RepositoryServer, error) {
@@ -35,12 +118,28 @@ func Initialize() (*RepositoryServer, error) {
 	clusterClient := cluster.New(clientset)
 
 	return &RepositoryServer{
-		Repositories:     make(map[string]*gopassRepo),
-		Client:           &clusterClient,
-		KubernetesClient: clientset,
+		Repositories:        make(map[string]*gopassRepo),
+		Client:              &clusterClient,
+		KubernetesClient:    clientset,
+		LegacyFieldManagers: legacyFieldManagersOf(os.Getenv(legacyFieldManagersVariable)),
 	}, nil
 }
 
+// legacyFieldManagersVariable configures a comma separated list of field managers used before server-side apply.
+const legacyFieldManagersVariable = "GOPASS_LEGACY_FIELD_MANAGERS"
+
+// legacyFieldManagersOf parses a comma separated list of field managers, ignoring empty names.
+func legacyFieldManagersOf(value string) []string {
+	managers := make([]string, 0)
+	for _, manager := range strings.Split(value, ",") {
+		manager = strings.TrimSpace(manager)
+		if manager != "" {
+			managers = append(managers, manager)
+		}
+	}
+	return managers
+}
+
 func createNewClientset() (kubernetes.Interface, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -56,7 +155,7 @@ func createNewClientset() (kubernetes.Interface, error) {
 }
 
 func (r *RepositoryServer) InitializeRepository(ctx context.Context, repositoryInitialization *gopass_repository.RepositoryInitialization) (*gopass_repository.RepositoryResponse, error) {
-	err := r.initializeRepository(ctx, repositoryInitialization)
+	commitHash, err := r.initializeRepository(ctx, repositoryInitialization)
 	if err != nil {
 		return &gopass_repository.RepositoryResponse{
 			Successful:   false,
@@ -67,10 +166,11 @@ func (r *RepositoryServer) InitializeRepository(ctx context.Context, repositoryI
 	return &gopass_repository.RepositoryResponse{
 		Successful:   true,
 		ErrorMessage: "",
+		CommitHash:   commitHash,
 	}, nil
 }
 func (r *RepositoryServer) UpdateRepository(ctx context.Context, repository *gopass_repository.Repository) (*gopass_repository.RepositoryResponse, error) {
-	err := r.updateRepository(ctx, repository)
+	commitHash, err := r.updateRepository(ctx, repository)
 
 	if err != nil {
 		return &gopass_repository.RepositoryResponse{
@@ -82,29 +182,109 @@ func (r *RepositoryServer) UpdateRepository(ctx context.Context, repository *gop
 	return &gopass_repository.RepositoryResponse{
 		Successful:   true,
 		ErrorMessage: "",
+		CommitHash:   commitHash,
 	}, nil
 }
 func (r *RepositoryServer) UpdateAllPasswords(ctx context.Context, repository *gopass_repository.Repository) (*gopass_repository.RepositoryResponse, error) {
-	err := r.updateAllPasswords(ctx, repository)
+	result, err := r.updateAllPasswords(ctx, repository)
 
+	response := &gopass_repository.RepositoryResponse{
+		Successful:     true,
+		ErrorMessage:   "",
+		CommitHash:     result.commitHash,
+		SyncedEntries:  int32(result.syncedEntries),
+		Collisions:     result.collisions,
+		TemplateErrors: result.templateErrors,
+		StaleKeys:      result.staleKeys,
+		Updated:        result.updated,
+		Unchanged:      result.unchanged,
+		Untargeted:     result.untargeted,
+	}
 	if err != nil {
-		return &gopass_repository.RepositoryResponse{
-			Successful:   false,
-			ErrorMessage: fmt.Sprintf("unable to update passwords: %v", err),
-		}, err
+		response.Successful = false
+		response.ErrorMessage = fmt.Sprintf("unable to update passwords: %v", err)
+		return response, syncError(err, result)
 	}
+	return response, nil
+}
 
-	return &gopass_repository.RepositoryResponse{
-		Successful:   true,
-		ErrorMessage: "",
-	}, nil
+// syncError attaches the colliding keys, template errors and stale keys found before the sync failed to its error, as
+// the response of a failed RPC is not sent to the client.
+func syncError(err error, result syncResult) error {
+	messages := make([]proto.Message, 0, len(result.collisions)+len(result.templateErrors)+len(result.staleKeys))
+	for _, collision := range result.collisions {
+		messages = append(messages, collision)
+	}
+	for _, templateError := range result.templateErrors {
+		messages = append(messages, templateError)
+	}
+	for _, staleKey := range result.staleKeys {
+		messages = append(messages, staleKey)
+	}
+	if len(messages) == 0 {
+		return err
+	}
+
+	details := make([]*anypb.Any, 0, len(messages))
+	for _, message := range messages {
+		detail, detailErr := anypb.New(message)
+		if detailErr != nil {
+			log.Printf("unable to attach details to error: %v", detailErr)
+			return err
+		}
+		details = append(details, detail)
+	}
+
+	return status.FromProto(&spb.Status{
+		Code:    int32(codes.FailedPrecondition),
+		Message: err.Error(),
+		Details: details,
+	}).Err()
+}
+
+// RemoveRepository removes the repository along with its clone, gopass configuration and GnuPG home. Removing an
+// unknown repository is successful, as it may already have been removed or the server may have been restarted.
+func (r *RepositoryServer) RemoveRepository(_ context.Context, repository *gopass_repository.Repository) (*gopass_repository.RepositoryResponse, error) {
+	log.Printf("RemoveRepository called with: %s", repository.RepositoryURL)
+
+	repo, ok := r.removeRepository(repository.RepositoryURL)
+	if !ok {
+		log.Printf("repository with URL '%s' not found, nothing to remove", repository.RepositoryURL)
+		return &gopass_repository.RepositoryResponse{Successful: true}, nil
+	}
+
+	// wait for running pulls and syncs of the repository
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.remove()
+
+	return &gopass_repository.RepositoryResponse{Successful: true}, nil
 }
 
+// DeleteSecret deletes the Secrets and ConfigMaps targeted by the repository as well as the ones labeled as written for
+// it, which are no longer targeted.
 func (r *RepositoryServer) DeleteSecret(ctx context.Context, repository *gopass_repository.Repository) (*gopass_repository.RepositoryResponse, error) {
-	successful, err := r.deleteSecretMap(ctx, types.NamespacedName{
-		Namespace: repository.SecretName.Namespace,
-		Name:      repository.SecretName.Name,
-	})
+	owned, err := r.ownedResources(ctx, repository)
+	if err != nil {
+		return &gopass_repository.RepositoryResponse{
+			Successful:   false,
+			ErrorMessage: fmt.Sprintf("failed to list Secrets: %v", err),
+		}, nil
+	}
+
+	successful := true
+	for _, resource := range uniqueResources(append(targetResources(repository), owned...)) {
+		var deleted bool
+		if resource.kind == targetKindConfigMap {
+			deleted, err = r.deleteConfigMap(ctx, resource.name)
+		} else {
+			deleted, err = r.deleteSecretMap(ctx, resource.name)
+		}
+		if !deleted {
+			successful = false
+			break
+		}
+	}
 
 	return &gopass_repository.RepositoryResponse{
 		Successful:   successful,
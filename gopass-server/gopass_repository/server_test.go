@@ -0,0 +1,211 @@
+package gopass_repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const parallelCalls = 8
+
+func TestInitializeRepositoryConcurrently(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       &cluster.KubernetesTestClient{},
+	}
+
+	repositoryInitialization := &gopass_repository.RepositoryInitialization{
+		Repository: &gopass_repository.Repository{
+			RepositoryURL:  repoDir,
+			Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		},
+	}
+
+	commitHashes := make([]string, parallelCalls)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelCalls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := r.InitializeRepository(context.Background(), repositoryInitialization)
+			if err != nil {
+				t.Errorf("not able to initialize repository: %v", err)
+				return
+			}
+			commitHashes[i] = response.CommitHash
+		}(i)
+	}
+	wg.Wait()
+
+	if len(r.Repositories) != 1 {
+		t.Errorf("%d repositories registered, wanted 1", len(r.Repositories))
+	}
+	for _, commitHash := range commitHashes {
+		if commitHash != commitHashes[0] {
+			t.Errorf("initialization returned commits %v, wanted all to be the same", commitHashes)
+			break
+		}
+	}
+
+	r.Repositories[repoDir].remove()
+}
+
+func TestRepositoryServerConcurrentRPCs(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+
+	r := &RepositoryServer{
+		Repositories:     map[string]*gopassRepo{},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: newApplyClientset(),
+	}
+
+	repository := &gopass_repository.Repository{
+		RepositoryURL:  repoDir,
+		Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+		SecretName: &gopass_repository.NamespacedName{
+			Namespace: "testNamespace",
+			Name:      "someSecret",
+		},
+	}
+
+	_, err := r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{Repository: repository})
+	if err != nil {
+		t.Errorf("not able to initialize repository: %v", err)
+		return
+	}
+	defer r.Repositories[repoDir].remove()
+
+	calls := []func() (*gopass_repository.RepositoryResponse, error){
+		func() (*gopass_repository.RepositoryResponse, error) {
+			return r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{Repository: repository})
+		},
+		func() (*gopass_repository.RepositoryResponse, error) {
+			return r.UpdateRepository(context.Background(), repository)
+		},
+		func() (*gopass_repository.RepositoryResponse, error) {
+			return r.UpdateAllPasswords(context.Background(), repository)
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelCalls; i++ {
+		for _, call := range calls {
+			wg.Add(1)
+			go func(call func() (*gopass_repository.RepositoryResponse, error)) {
+				defer wg.Done()
+				response, err := call()
+				if err != nil || !response.Successful {
+					t.Errorf("call failed: %v, %s", err, response.GetErrorMessage())
+				}
+			}(call)
+		}
+	}
+	wg.Wait()
+}
+
+func TestRemoveRepository(t *testing.T) {
+	repoDir := initializeTestRepository(t)
+
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{},
+		Client:       &cluster.KubernetesTestClient{},
+	}
+
+	repository := &gopass_repository.Repository{
+		RepositoryURL:  repoDir,
+		Authentication: &gopass_repository.Authentication{Namespace: "testNameSpace"},
+	}
+
+	_, err := r.InitializeRepository(context.Background(), &gopass_repository.RepositoryInitialization{Repository: repository})
+	if err != nil {
+		t.Errorf("not able to initialize repository: %v", err)
+		return
+	}
+	repo := r.Repositories[repoDir]
+
+	for i := 0; i < 2; i++ {
+		response, err := r.RemoveRepository(context.Background(), repository)
+		if err != nil || !response.Successful {
+			t.Errorf("not able to remove repository: %v, %s", err, response.GetErrorMessage())
+		}
+	}
+
+	if _, ok := r.Repositories[repoDir]; ok {
+		t.Errorf("repository is still registered")
+	}
+	for _, directory := range []string{repo.directory, repo.homeDirectory} {
+		if _, err := os.Stat(directory); !os.IsNotExist(err) {
+			t.Errorf("directory '%s' has not been removed", directory)
+		}
+	}
+
+	response, err := r.UpdateRepository(context.Background(), repository)
+	if err == nil || response.Successful {
+		t.Errorf("updated removed repository")
+	}
+}
+
+func TestSyncErrorAttachesReports(t *testing.T) {
+	secretName := &gopass_repository.NamespacedName{Namespace: "namespace", Name: "secret"}
+	collision := &gopass_repository.KeyCollision{SecretName: secretName, Key: "key", Entries: []string{"a/key", "b/key"}}
+	templateError := &gopass_repository.TemplateError{SecretName: secretName, Key: "template", Message: "broken"}
+	staleKey := &gopass_repository.StaleKey{SecretName: secretName, Key: "stale"}
+
+	err := syncError(fmt.Errorf("colliding keys"), syncResult{
+		collisions:     []*gopass_repository.KeyCollision{collision},
+		templateErrors: []*gopass_repository.TemplateError{templateError},
+		staleKeys:      []*gopass_repository.StaleKey{staleKey},
+	})
+
+	syncStatus := status.Convert(err)
+	if syncStatus.Code() != codes.FailedPrecondition || syncStatus.Message() != "colliding keys" {
+		t.Errorf("unexpected status: %v", syncStatus)
+	}
+	details := syncStatus.Details()
+	wanted := []proto.Message{collision, templateError, staleKey}
+	if len(details) != len(wanted) {
+		t.Fatalf("expected %d details, got: %v", len(wanted), details)
+	}
+	for i, detail := range details {
+		message, ok := detail.(proto.Message)
+		if !ok || !proto.Equal(message, wanted[i]) {
+			t.Errorf("detail %d was %v, wanted %v", i, detail, wanted[i])
+		}
+	}
+}
+
+func TestSyncErrorWithoutReports(t *testing.T) {
+	err := fmt.Errorf("some error")
+	if syncErr := syncError(err, syncResult{}); syncErr != err {
+		t.Errorf("syncError() = %v, wanted the unchanged error", syncErr)
+	}
+}
+
+func TestLegacyFieldManagersOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "unset", value: "", want: []string{}},
+		{name: "single manager", value: "gopass_server", want: []string{"gopass_server"}},
+		{name: "list of managers", value: "gopass_server, gopass-server,,", want: []string{"gopass_server", "gopass-server"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legacyFieldManagersOf(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("legacyFieldManagersOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
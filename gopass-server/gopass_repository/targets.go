@@ -0,0 +1,423 @@
+package gopass_repository
+
+import (
+	"fmt"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	targetKindSecret    = "Secret"
+	targetKindConfigMap = "ConfigMap"
+)
+
+// Labels identifying the GopassRepository the Secrets and ConfigMaps have been written for, like the repository server.
+const (
+	repositoryNameLabel      = "gopassRepoName"
+	repositoryNamespaceLabel = "gopassRepoNamespace"
+)
+
+// secretTarget describes which entries are written to which keys of a single Secret.
+type secretTarget struct {
+	name            types.NamespacedName
+	prefix          string
+	include         entryFilter
+	selectAll       bool
+	data            []*gopass_repository.KeyMapping
+	naming          keyNaming
+	collisionPolicy string
+	includeFields   bool
+	templates       map[string]string
+	secretType      corev1.SecretType
+	registries      []*gopass_repository.RegistryCredentials
+	kind            string
+	labels          map[string]string
+	annotations     map[string]string
+}
+
+// targetResource identifies a Secret or ConfigMap managed for a repository.
+type targetResource struct {
+	kind string
+	name types.NamespacedName
+}
+
+// secretContent is the data of a target Secret along with the problems that occurred while creating it.
+type secretContent struct {
+	data           map[string]string
+	collisions     []*gopass_repository.KeyCollision
+	templateErrors []*gopass_repository.TemplateError
+}
+
+// createSecretTargets returns the Secrets the entries of the repository are written to.
+// Without explicitly given targets all entries are written to the Secret given by SecretName. Every target is labeled
+// with the GopassRepository it is written for.
+func createSecretTargets(repository *gopass_repository.Repository) ([]secretTarget, error) {
+	err := validateCollisionPolicy(repository.CollisionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repository.Secrets) == 0 {
+		if repository.SecretName == nil {
+			return nil, fmt.Errorf("neither a secret name nor secret targets given")
+		}
+
+		naming, err := newKeyNaming(repository.KeyNaming, repository.KeyNamingPrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		return []secretTarget{
+			{
+				name:            toNamespacedName(repository.SecretName),
+				selectAll:       true,
+				naming:          naming,
+				collisionPolicy: repository.CollisionPolicy,
+				includeFields:   repository.IncludeFields,
+				templates:       repository.Templates,
+				secretType:      corev1.SecretTypeOpaque,
+				kind:            targetKindSecret,
+				labels:          mergeMaps(repository.Labels, ownerLabels(repository)),
+				annotations:     repository.Annotations,
+			},
+		}, nil
+	}
+
+	targets := make([]secretTarget, 0, len(repository.Secrets))
+	names := make(map[types.NamespacedName]bool)
+
+	for _, target := range repository.Secrets {
+		if target.Name == nil || target.Name.Name == "" {
+			return nil, fmt.Errorf("secret target without name")
+		}
+
+		name := toNamespacedName(target.Name)
+		if names[name] {
+			return nil, fmt.Errorf("secret '%s' is targeted more than once", name)
+		}
+		names[name] = true
+
+		include, err := newEntryFilter(target.Include, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern of secret '%s': %v", name, err)
+		}
+
+		for _, mapping := range target.Data {
+			if mapping.Key == "" || mapping.Path == "" {
+				return nil, fmt.Errorf("mapping of secret '%s' requires a key and a path", name)
+			}
+		}
+
+		kind, err := targetKind(target.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kind of secret '%s': %v", name, err)
+		}
+
+		secretType, err := toSecretType(target.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type of secret '%s': %v", name, err)
+		}
+		if kind == targetKindConfigMap && (target.Type != "" || len(target.Registries) > 0) {
+			return nil, fmt.Errorf("config map '%s' does not support a type or registries", name)
+		}
+
+		for _, registry := range target.Registries {
+			if registry.Server == "" || registry.Path == "" {
+				return nil, fmt.Errorf("registry of secret '%s' requires a server and a path", name)
+			}
+		}
+		if len(target.Registries) > 0 && secretType != corev1.SecretTypeDockerConfigJson {
+			return nil, fmt.Errorf("registries of secret '%s' require type '%s'", name, corev1.SecretTypeDockerConfigJson)
+		}
+
+		namingPrefix := repository.KeyNamingPrefix
+		if namingPrefix == "" {
+			namingPrefix = target.Prefix
+		}
+		naming, err := newKeyNaming(repository.KeyNaming, namingPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key naming of secret '%s': %v", name, err)
+		}
+
+		targets = append(targets, secretTarget{
+			name:            name,
+			prefix:          target.Prefix,
+			include:         include,
+			selectAll:       target.Prefix == "" && len(target.Include) == 0 && len(target.Data) == 0 && len(target.Templates) == 0 && len(target.Registries) == 0,
+			data:            target.Data,
+			naming:          naming,
+			collisionPolicy: repository.CollisionPolicy,
+			includeFields:   repository.IncludeFields || target.IncludeFields,
+			templates:       target.Templates,
+			secretType:      secretType,
+			registries:      target.Registries,
+			kind:            kind,
+			labels:          mergeMaps(mergeMaps(repository.Labels, target.Labels), ownerLabels(repository)),
+			annotations:     mergeMaps(repository.Annotations, target.Annotations),
+		})
+	}
+
+	return targets, nil
+}
+
+// targetKind validates the kind of resource a target is written to. Secret is the default.
+func targetKind(kind string) (string, error) {
+	switch kind {
+	case "", targetKindSecret:
+		return targetKindSecret, nil
+	case targetKindConfigMap:
+		return targetKindConfigMap, nil
+	default:
+		return "", fmt.Errorf("unknown kind '%s'", kind)
+	}
+}
+
+// targetResources returns all Secrets and ConfigMaps managed for the repository.
+func targetResources(repository *gopass_repository.Repository) []targetResource {
+	if len(repository.Secrets) == 0 {
+		if repository.SecretName == nil {
+			return nil
+		}
+		return []targetResource{{kind: targetKindSecret, name: toNamespacedName(repository.SecretName)}}
+	}
+
+	resources := make([]targetResource, 0, len(repository.Secrets))
+	for _, target := range repository.Secrets {
+		if target.Name == nil {
+			continue
+		}
+		kind := targetKindSecret
+		if target.Kind == targetKindConfigMap {
+			kind = targetKindConfigMap
+		}
+		resources = append(resources, targetResource{kind: kind, name: toNamespacedName(target.Name)})
+	}
+	return resources
+}
+
+// ownerLabels returns the labels identifying the GopassRepository, whose name the controller passes as SecretName.
+func ownerLabels(repository *gopass_repository.Repository) map[string]string {
+	if repository.SecretName == nil || repository.SecretName.Name == "" {
+		return nil
+	}
+	return map[string]string{
+		repositoryNameLabel:      repository.SecretName.Name,
+		repositoryNamespaceLabel: repository.SecretName.Namespace,
+	}
+}
+
+// uniqueResources removes duplicates, keeping the order of the resources.
+func uniqueResources(resources []targetResource) []targetResource {
+	seen := make(map[targetResource]bool, len(resources))
+	unique := make([]targetResource, 0, len(resources))
+	for _, resource := range resources {
+		if seen[resource] {
+			continue
+		}
+		seen[resource] = true
+		unique = append(unique, resource)
+	}
+	return unique
+}
+
+func toNamespacedName(name *gopass_repository.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: name.Namespace,
+		Name:      name.Name,
+	}
+}
+
+// selects reports whether the entry is written to the target using a key derived from its name.
+func (t secretTarget) selects(name string) bool {
+	if t.selectAll {
+		return true
+	}
+
+	if t.prefix == "" && len(t.include.include) == 0 {
+		return false
+	}
+
+	if t.prefix != "" && !strings.HasPrefix(name, strings.TrimSuffix(t.prefix, "/")+"/") {
+		return false
+	}
+
+	return t.include.matches(name)
+}
+
+// wants reports whether the entry is needed by the target, either selected or explicitly mapped.
+func (t secretTarget) wants(name string) bool {
+	if t.selects(name) {
+		return true
+	}
+
+	for _, mapping := range t.data {
+		if mapping.Path == name {
+			return true
+		}
+	}
+	for _, registry := range t.registries {
+		if registry.Path == name {
+			return true
+		}
+	}
+	return false
+}
+
+// createSecretMap builds the data of the target Secret from the fetched entries and renders its templates.
+// Entries resulting in the same key are reported as collisions and handled according to the collision policy.
+// A failing template only omits its own key and is reported as template error.
+func (t secretTarget) createSecretMap(passwords []cluster.Secret, lookup *entryLookup) (secretContent, error) {
+	secretMap := make(map[string]string)
+	entriesByKey := make(map[string][]string)
+	passwordsByName := make(map[string]cluster.Secret, len(passwords))
+
+	setKey := func(key string, entry string, value string) {
+		entries, exists := entriesByKey[key]
+		entriesByKey[key] = append(entries, entry)
+		if exists && t.collisionPolicy == collisionPolicyKeepFirst {
+			return
+		}
+		secretMap[key] = value
+	}
+
+	for _, password := range passwords {
+		passwordsByName[password.Name] = password
+		if !t.selects(password.Name) {
+			continue
+		}
+
+		key := t.naming.key(password.Name)
+		setKey(key, password.Name, password.Password)
+
+		if t.includeFields {
+			for _, field := range sortedFieldNames(password.Fields) {
+				setKey(key+"."+kubernetesLegal(field), password.Name+":"+field, password.Fields[field])
+			}
+		}
+	}
+
+	for _, mapping := range t.data {
+		password, ok := passwordsByName[mapping.Path]
+		if !ok {
+			return secretContent{}, fmt.Errorf("entry '%s' mapped to key '%s' of secret '%s' not found", mapping.Path, mapping.Key, t.name)
+		}
+
+		if mapping.Field == "" {
+			setKey(mapping.Key, mapping.Path, password.Password)
+			continue
+		}
+
+		value, ok := password.Fields[mapping.Field]
+		if !ok {
+			return secretContent{}, fmt.Errorf("field '%s' of entry '%s' mapped to key '%s' of secret '%s' not found", mapping.Field, mapping.Path, mapping.Key, t.name)
+		}
+		setKey(mapping.Key, mapping.Path+":"+mapping.Field, value)
+	}
+
+	if len(t.registries) > 0 {
+		dockerConfigJson, err := createDockerConfigJson(t.name, t.registries, passwordsByName)
+		if err != nil {
+			return secretContent{}, err
+		}
+		setKey(corev1.DockerConfigJsonKey, "registries", dockerConfigJson)
+	}
+
+	templateErrors := make([]*gopass_repository.TemplateError, 0)
+	for _, key := range sortedFieldNames(t.templates) {
+		rendered, err := renderTemplate(key, t.templates[key], lookup)
+		if err != nil {
+			log.Printf("unable to render template '%s' of secret '%s': %v", key, t.name, err)
+			templateErrors = append(templateErrors, &gopass_repository.TemplateError{
+				SecretName: &gopass_repository.NamespacedName{
+					Namespace: t.name.Namespace,
+					Name:      t.name.Name,
+				},
+				Key:     key,
+				Message: err.Error(),
+			})
+			continue
+		}
+		setKey(key, "template:"+key, rendered)
+	}
+
+	collisions := make([]*gopass_repository.KeyCollision, 0)
+	for key, entries := range entriesByKey {
+		if len(entries) > 1 {
+			collisions = append(collisions, &gopass_repository.KeyCollision{
+				SecretName: &gopass_repository.NamespacedName{
+					Namespace: t.name.Namespace,
+					Name:      t.name.Name,
+				},
+				Key:     key,
+				Entries: entries,
+			})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Key < collisions[j].Key
+	})
+
+	content := secretContent{
+		data:           secretMap,
+		collisions:     collisions,
+		templateErrors: templateErrors,
+	}
+
+	if len(collisions) > 0 && (t.collisionPolicy == "" || t.collisionPolicy == collisionPolicyFail) {
+		return content, collisionError(t.name, collisions)
+	}
+
+	err := validateSecretType(t.name, t.secretType, secretMap)
+	if err != nil {
+		return content, err
+	}
+
+	return content, nil
+}
+
+// mergeMaps returns the union of both maps, values of override take precedence.
+func mergeMaps(base map[string]string, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
+
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collisionError(name types.NamespacedName, collisions []*gopass_repository.KeyCollision) error {
+	descriptions := make([]string, 0, len(collisions))
+	for _, collision := range collisions {
+		descriptions = append(descriptions, fmt.Sprintf("key '%s' is produced by entries '%s'", collision.Key, strings.Join(collision.Entries, "', '")))
+	}
+	return fmt.Errorf("colliding keys in secret '%s': %s", name, strings.Join(descriptions, "; "))
+}
+
+func wantedByAny(targets []secretTarget, name string) bool {
+	for _, target := range targets {
+		if target.wants(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,866 @@
+package gopass_repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gopasspw/gopass/pkg/gopass/apimock"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"github.com/mdreem/gopass-operator/pkg/apiclient/gopass_repository"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"reflect"
+	"testing"
+)
+
+func TestCreateSecretTargets(t *testing.T) {
+	tests := []struct {
+		name            string
+		repository      *gopass_repository.Repository
+		wantedNames     []types.NamespacedName
+		wantErr         bool
+		wantedErrorText string
+	}{
+		{
+			name: "secret name is used without explicit targets",
+			repository: &gopass_repository.Repository{
+				SecretName: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+			},
+			wantedNames: []types.NamespacedName{{Namespace: "testNamespace", Name: "someSecret"}},
+		},
+		{
+			name: "explicit targets replace the secret name",
+			repository: &gopass_repository.Repository{
+				SecretName: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "someSecret"},
+				Secrets: []*gopass_repository.SecretTarget{
+					{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"},
+					{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"}, Include: []string{"team-b/**"}},
+				},
+			},
+			wantedNames: []types.NamespacedName{
+				{Namespace: "testNamespace", Name: "team-a"},
+				{Namespace: "testNamespace", Name: "team-b"},
+			},
+		},
+		{
+			name: "secret targeted twice",
+			repository: &gopass_repository.Repository{
+				Secrets: []*gopass_repository.SecretTarget{
+					{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}},
+					{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}},
+				},
+			},
+			wantErr:         true,
+			wantedErrorText: "secret 'testNamespace/team-a' is targeted more than once",
+		},
+		{
+			name: "mapping without path",
+			repository: &gopass_repository.Repository{
+				Secrets: []*gopass_repository.SecretTarget{
+					{
+						Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"},
+						Data: []*gopass_repository.KeyMapping{{Key: "password"}},
+					},
+				},
+			},
+			wantErr:         true,
+			wantedErrorText: "mapping of secret 'testNamespace/team-a' requires a key and a path",
+		},
+		{
+			name:            "neither secret name nor targets",
+			repository:      &gopass_repository.Repository{},
+			wantErr:         true,
+			wantedErrorText: "neither a secret name nor secret targets given",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := createSecretTargets(tt.repository)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createSecretTargets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if err.Error() != tt.wantedErrorText {
+					t.Errorf("createSecretTargets() error = '%v', wantedErrorText '%v'", err, tt.wantedErrorText)
+				}
+				return
+			}
+
+			names := make([]types.NamespacedName, 0)
+			for _, target := range targets {
+				names = append(names, target.name)
+			}
+			if !reflect.DeepEqual(names, tt.wantedNames) {
+				t.Errorf("createSecretTargets() = %v, wanted %v", names, tt.wantedNames)
+			}
+		})
+	}
+}
+
+func TestSecretTarget_createSecretMap(t *testing.T) {
+	passwords := []cluster.Secret{
+		{Name: "team-a/prod/database", Password: "database password"},
+		{Name: "team-a/prod/token", Password: "token"},
+		{Name: "team-b/token", Password: "other token"},
+		{Name: "shared/registry", Password: "registry password", Fields: map[string]string{"username": "robot", "url": "registry.example.com"}},
+	}
+
+	tests := []struct {
+		name    string
+		target  *gopass_repository.SecretTarget
+		wanted  map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "entries selected by prefix",
+			target: &gopass_repository.SecretTarget{Prefix: "team-a/prod"},
+			wanted: map[string]string{
+				"team-a-prod-database": "database password",
+				"team-a-prod-token":    "token",
+			},
+		},
+		{
+			name:   "entries selected by prefix and include",
+			target: &gopass_repository.SecretTarget{Prefix: "team-a/", Include: []string{"**/token"}},
+			wanted: map[string]string{
+				"team-a-prod-token": "token",
+			},
+		},
+		{
+			name: "only explicitly mapped entries",
+			target: &gopass_repository.SecretTarget{
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "DB_PASSWORD", Path: "team-a/prod/database"},
+					{Key: "REGISTRY_PASSWORD", Path: "shared/registry"},
+				},
+			},
+			wanted: map[string]string{
+				"DB_PASSWORD":       "database password",
+				"REGISTRY_PASSWORD": "registry password",
+			},
+		},
+		{
+			name: "selected and mapped entries",
+			target: &gopass_repository.SecretTarget{
+				Include: []string{"team-b/*"},
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "registry", Path: "shared/registry"},
+				},
+			},
+			wanted: map[string]string{
+				"team-b-token": "other token",
+				"registry":     "registry password",
+			},
+		},
+		{
+			name:   "fields of selected entries",
+			target: &gopass_repository.SecretTarget{Prefix: "shared", IncludeFields: true},
+			wanted: map[string]string{
+				"shared-registry":          "registry password",
+				"shared-registry.username": "robot",
+				"shared-registry.url":      "registry.example.com",
+			},
+		},
+		{
+			name: "mapped fields",
+			target: &gopass_repository.SecretTarget{
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "REGISTRY_USER", Path: "shared/registry", Field: "username"},
+					{Key: "REGISTRY_PASSWORD", Path: "shared/registry"},
+				},
+			},
+			wanted: map[string]string{
+				"REGISTRY_USER":     "robot",
+				"REGISTRY_PASSWORD": "registry password",
+			},
+		},
+		{
+			name: "mapped field does not exist",
+			target: &gopass_repository.SecretTarget{
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "REGISTRY_EMAIL", Path: "shared/registry", Field: "email"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mapped entry does not exist",
+			target: &gopass_repository.SecretTarget{
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "missing", Path: "does/not/exist"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.target.Name = &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"}
+			targets, err := createSecretTargets(&gopass_repository.Repository{
+				Secrets: []*gopass_repository.SecretTarget{tt.target},
+			})
+			if err != nil {
+				t.Errorf("createSecretTargets() error = %v", err)
+				return
+			}
+
+			content, err := targets[0].createSecretMap(passwords, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createSecretMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(content.data, tt.wanted) {
+				t.Errorf("createSecretMap() = %v, wanted %v", content.data, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestSecretTarget_createSecretMapWithCollisions(t *testing.T) {
+	passwords := []cluster.Secret{
+		{Name: "team-a/db-password", Password: "first"},
+		{Name: "team-a/db_password", Password: "second"},
+		{Name: "team-a/token", Password: "token"},
+	}
+
+	tests := []struct {
+		name            string
+		collisionPolicy string
+		wanted          map[string]string
+		wantErr         bool
+		wantedErrorText string
+	}{
+		{
+			name:            "collisions fail by default",
+			collisionPolicy: "",
+			wantErr:         true,
+			wantedErrorText: "colliding keys in secret 'testNamespace/target': key 'team-a-db-password' is produced by entries 'team-a/db-password', 'team-a/db_password'",
+		},
+		{
+			name:            "keep first entry",
+			collisionPolicy: collisionPolicyKeepFirst,
+			wanted: map[string]string{
+				"team-a-db-password": "first",
+				"team-a-token":       "token",
+			},
+		},
+		{
+			name:            "keep last entry",
+			collisionPolicy: collisionPolicyKeepLast,
+			wanted: map[string]string{
+				"team-a-db-password": "second",
+				"team-a-token":       "token",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := createSecretTargets(&gopass_repository.Repository{
+				SecretName:      &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"},
+				CollisionPolicy: tt.collisionPolicy,
+			})
+			if err != nil {
+				t.Errorf("createSecretTargets() error = %v", err)
+				return
+			}
+
+			content, err := targets[0].createSecretMap(passwords, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createSecretMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.wantedErrorText {
+				t.Errorf("createSecretMap() error = '%v', wantedErrorText '%v'", err, tt.wantedErrorText)
+			}
+
+			collisions := content.collisions
+			if len(collisions) != 1 || collisions[0].Key != "team-a-db-password" || len(collisions[0].Entries) != 2 {
+				t.Errorf("createSecretMap() reported collisions %v, wanted one collision of key 'team-a-db-password'", collisions)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(content.data, tt.wanted) {
+				t.Errorf("createSecretMap() = %v, wanted %v", content.data, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestUpdateAllPasswordsReportsCollisionsOfFailedSync(t *testing.T) {
+	store := apimock.New()
+	for name, password := range map[string]string{
+		"team-a/db-password": "first",
+		"team-a/db_password": "second",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: newApplyClientset(),
+	}
+
+	response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"},
+	})
+	if err == nil || response.Successful {
+		t.Errorf("UpdateAllPasswords() succeeded, expected colliding keys to fail the sync")
+		return
+	}
+
+	wanted := []string{"team-a/db-password", "team-a/db_password"}
+	if len(response.Collisions) != 1 || !reflect.DeepEqual(response.Collisions[0].Entries, wanted) {
+		t.Errorf("UpdateAllPasswords() reported collisions %v, wanted entries %v", response.Collisions, wanted)
+	}
+
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Errorf("error carries %d details, wanted the colliding key", len(details))
+		return
+	}
+	collision, ok := details[0].(*gopass_repository.KeyCollision)
+	if !ok || collision.Key != "team-a-db-password" || !reflect.DeepEqual(collision.Entries, wanted) {
+		t.Errorf("error carries %v, wanted collision of key 'team-a-db-password'", details[0])
+	}
+}
+
+func TestUpdateAllPasswordsSyncsRemainingTargetsOfFailedSync(t *testing.T) {
+	store := apimock.New()
+	for name, password := range map[string]string{
+		"team-a/db-password": "first",
+		"team-a/db_password": "second",
+		"team-b/token":       "token",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		Secrets: []*gopass_repository.SecretTarget{
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"},
+			{
+				Name:      &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"},
+				Data:      []*gopass_repository.KeyMapping{{Key: "TOKEN", Path: "team-b/token"}},
+				Templates: map[string]string{"BROKEN": `{{ gopass "does/not/exist" }}`},
+			},
+		},
+	})
+	if err == nil || response.Successful {
+		t.Errorf("UpdateAllPasswords() succeeded, expected colliding keys to fail the sync")
+		return
+	}
+
+	secret, getErr := kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "team-b", metav1.GetOptions{})
+	if getErr != nil {
+		t.Errorf("target following the failed one was not synced: %v", getErr)
+	} else if wanted := map[string]string{"TOKEN": "token"}; !reflect.DeepEqual(secretData(secret), wanted) {
+		t.Errorf("secret 'team-b' contains %v, wanted %v", secretData(secret), wanted)
+	}
+
+	if len(response.Collisions) != 1 || response.Collisions[0].SecretName.Name != "team-a" {
+		t.Errorf("UpdateAllPasswords() reported collisions %v, wanted the collision of 'team-a'", response.Collisions)
+	}
+	if len(response.TemplateErrors) != 1 || response.TemplateErrors[0].Key != "BROKEN" {
+		t.Errorf("UpdateAllPasswords() reported template errors %v, wanted the error of key 'BROKEN'", response.TemplateErrors)
+	}
+	if len(response.Updated) != 1 || response.Updated[0].Name != "team-b" {
+		t.Errorf("UpdateAllPasswords() reported updated secrets %v, wanted 'team-b'", response.Updated)
+	}
+
+	details := status.Convert(err).Details()
+	if len(details) != 2 {
+		t.Errorf("error carries %d details, wanted the colliding key and the template error", len(details))
+		return
+	}
+	if _, ok := details[1].(*gopass_repository.TemplateError); !ok {
+		t.Errorf("error carries %v, wanted the template error of key 'BROKEN'", details[1])
+	}
+}
+
+func TestUpdateAllPasswordsReturnsPartialResultOfFailedWrite(t *testing.T) {
+	store := apimock.New()
+	for name, password := range map[string]string{
+		"team-a/token": "token a",
+		"team-b/token": "token b",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	kubernetesClient := newApplyClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "testNamespace",
+			Name:          "team-b",
+			ManagedFields: []metav1.ManagedFieldsEntry{managedFieldsEntry(fieldManager, []string{"team-b-token", "removed"})},
+		},
+		Data: map[string][]byte{"team-b-token": []byte("token b"), "removed": []byte("old")},
+	})
+	kubernetesClient.PrependReactor("patch", "secrets", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		if action.(clientgotesting.PatchAction).GetName() == "team-a" {
+			return true, nil, fmt.Errorf("apply of 'team-a' rejected")
+		}
+		return false, nil, nil
+	})
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		PrunePolicy:   prunePolicyKeep,
+		Secrets: []*gopass_repository.SecretTarget{
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"},
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"}, Prefix: "team-b"},
+		},
+	})
+	if err == nil || response.Successful {
+		t.Errorf("UpdateAllPasswords() succeeded, expected the rejected apply to fail the sync")
+		return
+	}
+
+	if response.SyncedEntries != 2 {
+		t.Errorf("received %d synced entries, expected %d", response.SyncedEntries, 2)
+	}
+	if len(response.Updated) != 1 || response.Updated[0].Name != "team-b" {
+		t.Errorf("UpdateAllPasswords() reported updated secrets %v, wanted 'team-b'", response.Updated)
+	}
+	if len(response.StaleKeys) != 1 || response.StaleKeys[0].Key != "removed" {
+		t.Errorf("UpdateAllPasswords() reported stale keys %v, wanted 'removed'", response.StaleKeys)
+	}
+
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Errorf("error carries %d details, wanted the stale key", len(details))
+	}
+}
+
+func TestCreateSecretTargetsWithUnknownCollisionPolicy(t *testing.T) {
+	_, err := createSecretTargets(&gopass_repository.Repository{
+		SecretName:      &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"},
+		CollisionPolicy: "Overwrite",
+	})
+	if err == nil || err.Error() != "unknown collision policy 'Overwrite'" {
+		t.Errorf("createSecretTargets() error = %v, expected unknown collision policy", err)
+	}
+}
+
+func TestUpdateAllPasswordsWithMultipleTargets(t *testing.T) {
+	store := &recordingStore{MockAPI: apimock.New()}
+	for name, password := range map[string]string{
+		"team-a/database": "database password",
+		"team-b/token":    "token",
+		"unused/entry":    "not needed",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		Secrets: []*gopass_repository.SecretTarget{
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"},
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"},
+				Data: []*gopass_repository.KeyMapping{{Key: "TOKEN", Path: "team-b/token"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+
+	if response.SyncedEntries != 2 {
+		t.Errorf("received %d synced entries, expected %d", response.SyncedEntries, 2)
+	}
+
+	for _, fetched := range store.fetched {
+		if fetched == "unused/entry" {
+			t.Errorf("entry '%s' was decrypted without being targeted", fetched)
+		}
+	}
+
+	wantedSecrets := map[string]map[string]string{
+		"team-a": {"team-a-database": "database password"},
+		"team-b": {"TOKEN": "token"},
+	}
+	for name, wantedData := range wantedSecrets {
+		secret, err := kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("unable to get secret '%s': %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(secretData(secret), wantedData) {
+			t.Errorf("secret '%s' contains %v, wanted %v", name, secretData(secret), wantedData)
+		}
+	}
+}
+
+func TestDeleteSecretDeletesAllTargets(t *testing.T) {
+	kubernetesClient := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "team-a"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "team-b"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "config"}},
+	)
+	r := &RepositoryServer{
+		KubernetesClient: kubernetesClient,
+	}
+
+	response, err := r.DeleteSecret(context.Background(), &gopass_repository.Repository{
+		Secrets: []*gopass_repository.SecretTarget{
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}},
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"}},
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "config"}, Kind: "ConfigMap"},
+		},
+	})
+	if err != nil || !response.Successful {
+		t.Errorf("DeleteSecret() not successful: %v", err)
+		return
+	}
+
+	configMaps, err := kubernetesClient.CoreV1().ConfigMaps("testNamespace").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Errorf("unable to list config maps: %v", err)
+		return
+	}
+	if len(configMaps.Items) != 0 {
+		t.Errorf("%d config maps remained, expected all to be deleted", len(configMaps.Items))
+	}
+
+	secrets, err := kubernetesClient.CoreV1().Secrets("testNamespace").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Errorf("unable to list secrets: %v", err)
+		return
+	}
+	if len(secrets.Items) != 0 {
+		t.Errorf("%d secrets remained, expected all to be deleted", len(secrets.Items))
+	}
+}
+
+func TestUpdateAllPasswordsWithConfigMapTarget(t *testing.T) {
+	store := apimock.New()
+	err := store.Set(context.Background(), "config/endpoint", &apimock.Secret{Buf: []byte("https://api.example.com\nregion: eu\n")})
+	if err != nil {
+		t.Errorf("unable to set key in store: %v", err)
+		return
+	}
+
+	kubernetesClient := newApplyClientset()
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	_, err = r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+		RepositoryURL: "testUrl",
+		Secrets: []*gopass_repository.SecretTarget{
+			{
+				Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "config"},
+				Kind: "ConfigMap",
+				Data: []*gopass_repository.KeyMapping{
+					{Key: "ENDPOINT", Path: "config/endpoint"},
+					{Key: "REGION", Path: "config/endpoint", Field: "region"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("UpdateAllPasswords() error = %v", err)
+		return
+	}
+
+	configMap, err := kubernetesClient.CoreV1().ConfigMaps("testNamespace").Get(context.Background(), "config", metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("unable to get config map: %v", err)
+		return
+	}
+	wantedData := map[string]string{"ENDPOINT": "https://api.example.com", "REGION": "eu"}
+	if !reflect.DeepEqual(configMap.Data, wantedData) {
+		t.Errorf("config map contains %v, wanted %v", configMap.Data, wantedData)
+	}
+
+	_, err = kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "config", metav1.GetOptions{})
+	if err == nil {
+		t.Errorf("a secret was created for a config map target")
+	}
+}
+
+func TestCreateSecretTargetsWithInvalidKind(t *testing.T) {
+	for _, target := range []*gopass_repository.SecretTarget{
+		{Kind: "Deployment"},
+		{Kind: "ConfigMap", Type: "kubernetes.io/tls"},
+	} {
+		target.Name = &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "target"}
+		_, err := createSecretTargets(&gopass_repository.Repository{
+			Secrets: []*gopass_repository.SecretTarget{target},
+		})
+		if err == nil {
+			t.Errorf("createSecretTargets() did not return an error for kind '%s' and type '%s'", target.Kind, target.Type)
+		}
+	}
+}
+
+func TestUpdateAllPasswordsDeletesRemovedTargets(t *testing.T) {
+	store := apimock.New()
+	for name, password := range map[string]string{
+		"team-a/database": "database password",
+		"team-b/token":    "token",
+	} {
+		err := store.Set(context.Background(), name, &apimock.Secret{Buf: []byte(password)})
+		if err != nil {
+			t.Errorf("unable to set key in store: %v", err)
+			return
+		}
+	}
+
+	kubernetesClient := newApplyClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "unrelated"}},
+	)
+	r := &RepositoryServer{
+		Repositories: map[string]*gopassRepo{
+			"testUrl": {store: store},
+		},
+		Client:           &cluster.KubernetesTestClient{},
+		KubernetesClient: kubernetesClient,
+	}
+
+	teamA := &gopass_repository.SecretTarget{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"}
+	teamB := &gopass_repository.SecretTarget{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-b"}, Prefix: "team-b"}
+	config := &gopass_repository.SecretTarget{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "config"}, Prefix: "team-b", Kind: "ConfigMap"}
+
+	steps := []struct {
+		name             string
+		secrets          []*gopass_repository.SecretTarget
+		wantedSecrets    []string
+		wantedConfigMaps []string
+	}{
+		{
+			name:             "all targets are written",
+			secrets:          []*gopass_repository.SecretTarget{teamA, teamB, config},
+			wantedSecrets:    []string{"team-a", "team-b", "unrelated"},
+			wantedConfigMaps: []string{"config"},
+		},
+		{
+			name:             "removed targets are deleted",
+			secrets:          []*gopass_repository.SecretTarget{teamA},
+			wantedSecrets:    []string{"team-a", "unrelated"},
+			wantedConfigMaps: []string{},
+		},
+		{
+			name:             "explicit targets are deleted when switching to the default secret",
+			secrets:          nil,
+			wantedSecrets:    []string{"repository", "unrelated"},
+			wantedConfigMaps: []string{},
+		},
+	}
+	for _, step := range steps {
+		_, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+			RepositoryURL: "testUrl",
+			SecretName:    &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "repository"},
+			Secrets:       step.secrets,
+		})
+		if err != nil {
+			t.Errorf("%s: UpdateAllPasswords() error = %v", step.name, err)
+			return
+		}
+
+		secrets, err := kubernetesClient.CoreV1().Secrets("testNamespace").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Errorf("unable to list secrets: %v", err)
+			return
+		}
+		secretNames := make([]string, 0)
+		for _, secret := range secrets.Items {
+			secretNames = append(secretNames, secret.Name)
+		}
+		if !reflect.DeepEqual(secretNames, step.wantedSecrets) {
+			t.Errorf("%s: secrets %v exist, wanted %v", step.name, secretNames, step.wantedSecrets)
+		}
+
+		configMaps, err := kubernetesClient.CoreV1().ConfigMaps("testNamespace").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Errorf("unable to list config maps: %v", err)
+			return
+		}
+		configMapNames := make([]string, 0)
+		for _, configMap := range configMaps.Items {
+			configMapNames = append(configMapNames, configMap.Name)
+		}
+		if !reflect.DeepEqual(configMapNames, step.wantedConfigMaps) {
+			t.Errorf("%s: config maps %v exist, wanted %v", step.name, configMapNames, step.wantedConfigMaps)
+		}
+	}
+}
+
+func TestUpdateAllPasswordsRespectsPoliciesForRemovedTargets(t *testing.T) {
+	owner := map[string]string{repositoryNameLabel: "repository", repositoryNamespaceLabel: "testNamespace"}
+	tests := []struct {
+		name             string
+		prunePolicy      string
+		deletionPolicy   string
+		wantedUntargeted []string
+		wantedLabels     map[string]string
+	}{
+		{
+			name:             "removed targets are kept and reported with prune policy Keep",
+			prunePolicy:      prunePolicyKeep,
+			wantedUntargeted: []string{"team-b"},
+			wantedLabels:     mergeMaps(map[string]string{"team": "b"}, owner),
+		},
+		{
+			name:             "removed targets are orphaned with deletion policy Orphan",
+			deletionPolicy:   deletionPolicyOrphan,
+			wantedUntargeted: []string{},
+			wantedLabels:     map[string]string{"team": "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := apimock.New()
+			err := store.Set(context.Background(), "team-a/database", &apimock.Secret{Buf: []byte("database password")})
+			if err != nil {
+				t.Errorf("unable to set key in store: %v", err)
+				return
+			}
+
+			kubernetesClient := newApplyClientset(
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Namespace: "testNamespace",
+					Name:      "team-b",
+					Labels:    mergeMaps(map[string]string{"team": "b"}, owner),
+				}},
+			)
+			r := &RepositoryServer{
+				Repositories: map[string]*gopassRepo{
+					"testUrl": {store: store},
+				},
+				Client:           &cluster.KubernetesTestClient{},
+				KubernetesClient: kubernetesClient,
+			}
+
+			response, err := r.UpdateAllPasswords(context.Background(), &gopass_repository.Repository{
+				RepositoryURL:  "testUrl",
+				SecretName:     &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "repository"},
+				PrunePolicy:    tt.prunePolicy,
+				DeletionPolicy: tt.deletionPolicy,
+				Secrets: []*gopass_repository.SecretTarget{
+					{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}, Prefix: "team-a"},
+				},
+			})
+			if err != nil {
+				t.Errorf("UpdateAllPasswords() error = %v", err)
+				return
+			}
+
+			untargeted := make([]string, 0)
+			for _, name := range response.Untargeted {
+				untargeted = append(untargeted, name.Name)
+			}
+			if !reflect.DeepEqual(untargeted, tt.wantedUntargeted) {
+				t.Errorf("UpdateAllPasswords() reported untargeted secrets %v, wanted %v", untargeted, tt.wantedUntargeted)
+			}
+
+			secret, err := kubernetesClient.CoreV1().Secrets("testNamespace").Get(context.Background(), "team-b", metav1.GetOptions{})
+			if err != nil {
+				t.Errorf("removed target was deleted: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(secret.Labels, tt.wantedLabels) {
+				t.Errorf("removed target is labeled %v, wanted %v", secret.Labels, tt.wantedLabels)
+			}
+		})
+	}
+}
+
+func TestDeleteSecretDeletesNoLongerTargetedResources(t *testing.T) {
+	owner := map[string]string{repositoryNameLabel: "repository", repositoryNamespaceLabel: "testNamespace"}
+	kubernetesClient := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "team-a", Labels: owner}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "removed", Labels: owner}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "removed-config", Labels: owner}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testNamespace", Name: "unrelated"}},
+	)
+	r := &RepositoryServer{
+		KubernetesClient: kubernetesClient,
+	}
+
+	response, err := r.DeleteSecret(context.Background(), &gopass_repository.Repository{
+		SecretName: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "repository"},
+		Secrets: []*gopass_repository.SecretTarget{
+			{Name: &gopass_repository.NamespacedName{Namespace: "testNamespace", Name: "team-a"}},
+		},
+	})
+	if err != nil || !response.Successful {
+		t.Errorf("DeleteSecret() not successful: %v", err)
+		return
+	}
+
+	secrets, err := kubernetesClient.CoreV1().Secrets("testNamespace").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Errorf("unable to list secrets: %v", err)
+		return
+	}
+	if len(secrets.Items) != 1 || secrets.Items[0].Name != "unrelated" {
+		t.Errorf("secrets %v remained, expected only the unrelated secret", secrets.Items)
+	}
+
+	configMaps, err := kubernetesClient.CoreV1().ConfigMaps("testNamespace").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Errorf("unable to list config maps: %v", err)
+		return
+	}
+	if len(configMaps.Items) != 0 {
+		t.Errorf("%d config maps remained, expected all to be deleted", len(configMaps.Items))
+	}
+}
@@ -0,0 +1,106 @@
+package gopass_repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/mdreem/gopass-operator/gopass-server/gopass_repository/cluster"
+	"strings"
+	"text/template"
+)
+
+// entryLookup resolves entries referenced by templates. Entries that have already been fetched are reused,
+// others are decrypted on demand if they are not excluded by the filter of the repository.
+type entryLookup struct {
+	ctx     context.Context
+	repo    *gopassRepo
+	filter  entryFilter
+	entries map[string]cluster.Secret
+}
+
+func newEntryLookup(ctx context.Context, repo *gopassRepo, filter entryFilter, passwords []cluster.Secret) *entryLookup {
+	entries := make(map[string]cluster.Secret, len(passwords))
+	for _, password := range passwords {
+		entries[password.Name] = password
+	}
+
+	return &entryLookup{
+		ctx:     ctx,
+		repo:    repo,
+		filter:  filter,
+		entries: entries,
+	}
+}
+
+func (l *entryLookup) get(name string) (cluster.Secret, error) {
+	if entry, ok := l.entries[name]; ok {
+		return entry, nil
+	}
+
+	if !l.filter.matches(name) {
+		return cluster.Secret{}, fmt.Errorf("entry '%s' is excluded from the sync", name)
+	}
+
+	entry, err := decryptEntry(l.ctx, l.repo, name)
+	if err != nil {
+		return cluster.Secret{}, fmt.Errorf("entry '%s' not found: %v", name, err)
+	}
+	l.entries[name] = entry
+
+	return entry, nil
+}
+
+func (l *entryLookup) templateFunctions() template.FuncMap {
+	return template.FuncMap{
+		"gopass": func(name string) (string, error) {
+			entry, err := l.get(name)
+			if err != nil {
+				return "", err
+			}
+			return entry.Password, nil
+		},
+		"field": func(name string, field string) (string, error) {
+			entry, err := l.get(name)
+			if err != nil {
+				return "", err
+			}
+			value, ok := entry.Fields[field]
+			if !ok {
+				return "", fmt.Errorf("field '%s' of entry '%s' not found", field, name)
+			}
+			return value, nil
+		},
+		"base64": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"indent": func(spaces int, value string) string {
+			padding := strings.Repeat(" ", spaces)
+			return padding + strings.Replace(value, "\n", "\n"+padding, -1)
+		},
+		"toJson": func(value interface{}) (string, error) {
+			marshalled, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(marshalled), nil
+		},
+	}
+}
+
+// renderTemplate renders a single output of a Secret. Entries are looked up using the functions gopass and field.
+func renderTemplate(key string, text string, lookup *entryLookup) (string, error) {
+	parsedTemplate, err := template.New(key).Option("missingkey=error").Funcs(lookup.templateFunctions()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered bytes.Buffer
+	err = parsedTemplate.Execute(&rendered, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
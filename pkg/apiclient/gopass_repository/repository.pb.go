@@ -29,21 +29,90 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type ResourceKeyReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Key  string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ResourceKeyReference) Reset() {
+	*x = ResourceKeyReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceKeyReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceKeyReference) ProtoMessage() {}
+
+func (x *ResourceKeyReference) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceKeyReference.ProtoReflect.Descriptor instead.
+func (*ResourceKeyReference) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResourceKeyReference) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ResourceKeyReference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResourceKeyReference) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
 type Authentication struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Username  string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	SecretRef string `protobuf:"bytes,3,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
-	SecretKey string `protobuf:"bytes,4,opt,name=secretKey,proto3" json:"secretKey,omitempty"`
+	Namespace             string                `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Username              string                `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	SecretRef             string                `protobuf:"bytes,3,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
+	SecretKey             string                `protobuf:"bytes,4,opt,name=secretKey,proto3" json:"secretKey,omitempty"`
+	SshKeyRef             string                `protobuf:"bytes,5,opt,name=sshKeyRef,proto3" json:"sshKeyRef,omitempty"`
+	SshKeyRefKey          string                `protobuf:"bytes,6,opt,name=sshKeyRefKey,proto3" json:"sshKeyRefKey,omitempty"`
+	SshKeyPassphraseKey   string                `protobuf:"bytes,7,opt,name=sshKeyPassphraseKey,proto3" json:"sshKeyPassphraseKey,omitempty"`
+	CaBundleRef           *ResourceKeyReference `protobuf:"bytes,8,opt,name=caBundleRef,proto3" json:"caBundleRef,omitempty"`
+	KnownHostsRef         *ResourceKeyReference `protobuf:"bytes,9,opt,name=knownHostsRef,proto3" json:"knownHostsRef,omitempty"`
+	InsecureIgnoreHostKey bool                  `protobuf:"varint,10,opt,name=insecureIgnoreHostKey,proto3" json:"insecureIgnoreHostKey,omitempty"`
 }
 
 func (x *Authentication) Reset() {
 	*x = Authentication{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[0]
+		mi := &file_gopass_repository_repository_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -56,7 +125,7 @@ func (x *Authentication) String() string {
 func (*Authentication) ProtoMessage() {}
 
 func (x *Authentication) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[0]
+	mi := &file_gopass_repository_repository_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -69,7 +138,7 @@ func (x *Authentication) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Authentication.ProtoReflect.Descriptor instead.
 func (*Authentication) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{0}
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Authentication) GetNamespace() string {
@@ -100,6 +169,48 @@ func (x *Authentication) GetSecretKey() string {
 	return ""
 }
 
+func (x *Authentication) GetSshKeyRef() string {
+	if x != nil {
+		return x.SshKeyRef
+	}
+	return ""
+}
+
+func (x *Authentication) GetSshKeyRefKey() string {
+	if x != nil {
+		return x.SshKeyRefKey
+	}
+	return ""
+}
+
+func (x *Authentication) GetSshKeyPassphraseKey() string {
+	if x != nil {
+		return x.SshKeyPassphraseKey
+	}
+	return ""
+}
+
+func (x *Authentication) GetCaBundleRef() *ResourceKeyReference {
+	if x != nil {
+		return x.CaBundleRef
+	}
+	return nil
+}
+
+func (x *Authentication) GetKnownHostsRef() *ResourceKeyReference {
+	if x != nil {
+		return x.KnownHostsRef
+	}
+	return nil
+}
+
+func (x *Authentication) GetInsecureIgnoreHostKey() bool {
+	if x != nil {
+		return x.InsecureIgnoreHostKey
+	}
+	return false
+}
+
 type NamespacedName struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -112,7 +223,7 @@ type NamespacedName struct {
 func (x *NamespacedName) Reset() {
 	*x = NamespacedName{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[1]
+		mi := &file_gopass_repository_repository_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -124,8 +235,641 @@ func (x *NamespacedName) String() string {
 
 func (*NamespacedName) ProtoMessage() {}
 
-func (x *NamespacedName) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[1]
+func (x *NamespacedName) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamespacedName.ProtoReflect.Descriptor instead.
+func (*NamespacedName) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NamespacedName) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *NamespacedName) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type KeyMapping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Path  string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Field string `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`
+}
+
+func (x *KeyMapping) Reset() {
+	*x = KeyMapping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyMapping) ProtoMessage() {}
+
+func (x *KeyMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyMapping.ProtoReflect.Descriptor instead.
+func (*KeyMapping) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KeyMapping) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyMapping) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *KeyMapping) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+type RegistryCredentials struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server        string `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	UsernameField string `protobuf:"bytes,3,opt,name=usernameField,proto3" json:"usernameField,omitempty"`
+}
+
+func (x *RegistryCredentials) Reset() {
+	*x = RegistryCredentials{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegistryCredentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistryCredentials) ProtoMessage() {}
+
+func (x *RegistryCredentials) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistryCredentials.ProtoReflect.Descriptor instead.
+func (*RegistryCredentials) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RegistryCredentials) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *RegistryCredentials) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RegistryCredentials) GetUsernameField() string {
+	if x != nil {
+		return x.UsernameField
+	}
+	return ""
+}
+
+type SecretTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          *NamespacedName        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Include       []string               `protobuf:"bytes,3,rep,name=include,proto3" json:"include,omitempty"`
+	Data          []*KeyMapping          `protobuf:"bytes,4,rep,name=data,proto3" json:"data,omitempty"`
+	IncludeFields bool                   `protobuf:"varint,5,opt,name=includeFields,proto3" json:"includeFields,omitempty"`
+	Templates     map[string]string      `protobuf:"bytes,6,rep,name=templates,proto3" json:"templates,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Type          string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	Registries    []*RegistryCredentials `protobuf:"bytes,8,rep,name=registries,proto3" json:"registries,omitempty"`
+	Kind          string                 `protobuf:"bytes,9,opt,name=kind,proto3" json:"kind,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations   map[string]string      `protobuf:"bytes,11,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SecretTarget) Reset() {
+	*x = SecretTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretTarget) ProtoMessage() {}
+
+func (x *SecretTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretTarget.ProtoReflect.Descriptor instead.
+func (*SecretTarget) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SecretTarget) GetName() *NamespacedName {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SecretTarget) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetData() []*KeyMapping {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetIncludeFields() bool {
+	if x != nil {
+		return x.IncludeFields
+	}
+	return false
+}
+
+func (x *SecretTarget) GetTemplates() map[string]string {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SecretTarget) GetRegistries() []*RegistryCredentials {
+	if x != nil {
+		return x.Registries
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SecretTarget) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SecretTarget) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type Repository struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RepositoryURL   string            `protobuf:"bytes,1,opt,name=repositoryURL,proto3" json:"repositoryURL,omitempty"`
+	Authentication  *Authentication   `protobuf:"bytes,2,opt,name=authentication,proto3" json:"authentication,omitempty"`
+	SecretName      *NamespacedName   `protobuf:"bytes,3,opt,name=SecretName,proto3" json:"SecretName,omitempty"`
+	Include         []string          `protobuf:"bytes,4,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude         []string          `protobuf:"bytes,5,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	Secrets         []*SecretTarget   `protobuf:"bytes,6,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	KeyNaming       string            `protobuf:"bytes,7,opt,name=keyNaming,proto3" json:"keyNaming,omitempty"`
+	KeyNamingPrefix string            `protobuf:"bytes,8,opt,name=keyNamingPrefix,proto3" json:"keyNamingPrefix,omitempty"`
+	CollisionPolicy string            `protobuf:"bytes,9,opt,name=collisionPolicy,proto3" json:"collisionPolicy,omitempty"`
+	IncludeFields   bool              `protobuf:"varint,10,opt,name=includeFields,proto3" json:"includeFields,omitempty"`
+	Templates       map[string]string `protobuf:"bytes,11,rep,name=templates,proto3" json:"templates,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PrunePolicy     string            `protobuf:"bytes,12,opt,name=prunePolicy,proto3" json:"prunePolicy,omitempty"`
+	Labels          map[string]string `protobuf:"bytes,13,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations     map[string]string `protobuf:"bytes,14,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Ref             string            `protobuf:"bytes,15,opt,name=ref,proto3" json:"ref,omitempty"`
+	Mounts          []*Mount          `protobuf:"bytes,16,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	StorePath       string            `protobuf:"bytes,17,opt,name=storePath,proto3" json:"storePath,omitempty"`
+	DeletionPolicy  string            `protobuf:"bytes,18,opt,name=deletionPolicy,proto3" json:"deletionPolicy,omitempty"`
+}
+
+func (x *Repository) Reset() {
+	*x = Repository{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Repository) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Repository) ProtoMessage() {}
+
+func (x *Repository) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Repository.ProtoReflect.Descriptor instead.
+func (*Repository) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Repository) GetRepositoryURL() string {
+	if x != nil {
+		return x.RepositoryURL
+	}
+	return ""
+}
+
+func (x *Repository) GetAuthentication() *Authentication {
+	if x != nil {
+		return x.Authentication
+	}
+	return nil
+}
+
+func (x *Repository) GetSecretName() *NamespacedName {
+	if x != nil {
+		return x.SecretName
+	}
+	return nil
+}
+
+func (x *Repository) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *Repository) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *Repository) GetSecrets() []*SecretTarget {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *Repository) GetKeyNaming() string {
+	if x != nil {
+		return x.KeyNaming
+	}
+	return ""
+}
+
+func (x *Repository) GetKeyNamingPrefix() string {
+	if x != nil {
+		return x.KeyNamingPrefix
+	}
+	return ""
+}
+
+func (x *Repository) GetCollisionPolicy() string {
+	if x != nil {
+		return x.CollisionPolicy
+	}
+	return ""
+}
+
+func (x *Repository) GetIncludeFields() bool {
+	if x != nil {
+		return x.IncludeFields
+	}
+	return false
+}
+
+func (x *Repository) GetTemplates() map[string]string {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+func (x *Repository) GetPrunePolicy() string {
+	if x != nil {
+		return x.PrunePolicy
+	}
+	return ""
+}
+
+func (x *Repository) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Repository) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *Repository) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *Repository) GetMounts() []*Mount {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
+}
+
+func (x *Repository) GetStorePath() string {
+	if x != nil {
+		return x.StorePath
+	}
+	return ""
+}
+
+func (x *Repository) GetDeletionPolicy() string {
+	if x != nil {
+		return x.DeletionPolicy
+	}
+	return ""
+}
+
+type Mount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MountPoint     string          `protobuf:"bytes,1,opt,name=mountPoint,proto3" json:"mountPoint,omitempty"`
+	RepositoryURL  string          `protobuf:"bytes,2,opt,name=repositoryURL,proto3" json:"repositoryURL,omitempty"`
+	Authentication *Authentication `protobuf:"bytes,3,opt,name=authentication,proto3" json:"authentication,omitempty"`
+	Ref            string          `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
+}
+
+func (x *Mount) Reset() {
+	*x = Mount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Mount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mount) ProtoMessage() {}
+
+func (x *Mount) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mount.ProtoReflect.Descriptor instead.
+func (*Mount) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Mount) GetMountPoint() string {
+	if x != nil {
+		return x.MountPoint
+	}
+	return ""
+}
+
+func (x *Mount) GetRepositoryURL() string {
+	if x != nil {
+		return x.RepositoryURL
+	}
+	return ""
+}
+
+func (x *Mount) GetAuthentication() *Authentication {
+	if x != nil {
+		return x.Authentication
+	}
+	return nil
+}
+
+func (x *Mount) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+type GpgKeyReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GpgKeyRef           string `protobuf:"bytes,1,opt,name=gpgKeyRef,proto3" json:"gpgKeyRef,omitempty"`
+	GpgKeyRefKey        string `protobuf:"bytes,2,opt,name=gpgKeyRefKey,proto3" json:"gpgKeyRefKey,omitempty"`
+	GpgPassphraseRef    string `protobuf:"bytes,3,opt,name=gpgPassphraseRef,proto3" json:"gpgPassphraseRef,omitempty"`
+	GpgPassphraseRefKey string `protobuf:"bytes,4,opt,name=gpgPassphraseRefKey,proto3" json:"gpgPassphraseRefKey,omitempty"`
+}
+
+func (x *GpgKeyReference) Reset() {
+	*x = GpgKeyReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GpgKeyReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GpgKeyReference) ProtoMessage() {}
+
+func (x *GpgKeyReference) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GpgKeyReference.ProtoReflect.Descriptor instead.
+func (*GpgKeyReference) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GpgKeyReference) GetGpgKeyRef() string {
+	if x != nil {
+		return x.GpgKeyRef
+	}
+	return ""
+}
+
+func (x *GpgKeyReference) GetGpgKeyRefKey() string {
+	if x != nil {
+		return x.GpgKeyRefKey
+	}
+	return ""
+}
+
+func (x *GpgKeyReference) GetGpgPassphraseRef() string {
+	if x != nil {
+		return x.GpgPassphraseRef
+	}
+	return ""
+}
+
+func (x *GpgKeyReference) GetGpgPassphraseRefKey() string {
+	if x != nil {
+		return x.GpgPassphraseRefKey
+	}
+	return ""
+}
+
+type RepositoryInitialization struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Repository      *Repository      `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	GpgKeyReference *GpgKeyReference `protobuf:"bytes,2,opt,name=gpgKeyReference,proto3" json:"gpgKeyReference,omitempty"`
+}
+
+func (x *RepositoryInitialization) Reset() {
+	*x = RepositoryInitialization{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gopass_repository_repository_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepositoryInitialization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepositoryInitialization) ProtoMessage() {}
+
+func (x *RepositoryInitialization) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -136,52 +880,52 @@ func (x *NamespacedName) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NamespacedName.ProtoReflect.Descriptor instead.
-func (*NamespacedName) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use RepositoryInitialization.ProtoReflect.Descriptor instead.
+func (*RepositoryInitialization) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *NamespacedName) GetNamespace() string {
+func (x *RepositoryInitialization) GetRepository() *Repository {
 	if x != nil {
-		return x.Namespace
+		return x.Repository
 	}
-	return ""
+	return nil
 }
 
-func (x *NamespacedName) GetName() string {
+func (x *RepositoryInitialization) GetGpgKeyReference() *GpgKeyReference {
 	if x != nil {
-		return x.Name
+		return x.GpgKeyReference
 	}
-	return ""
+	return nil
 }
 
-type Repository struct {
+type KeyCollision struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RepositoryURL  string          `protobuf:"bytes,1,opt,name=repositoryURL,proto3" json:"repositoryURL,omitempty"`
-	Authentication *Authentication `protobuf:"bytes,2,opt,name=authentication,proto3" json:"authentication,omitempty"`
-	SecretName     *NamespacedName `protobuf:"bytes,3,opt,name=SecretName,proto3" json:"SecretName,omitempty"`
+	SecretName *NamespacedName `protobuf:"bytes,1,opt,name=secretName,proto3" json:"secretName,omitempty"`
+	Key        string          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Entries    []string        `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
 }
 
-func (x *Repository) Reset() {
-	*x = Repository{}
+func (x *KeyCollision) Reset() {
+	*x = KeyCollision{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[2]
+		mi := &file_gopass_repository_repository_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Repository) String() string {
+func (x *KeyCollision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Repository) ProtoMessage() {}
+func (*KeyCollision) ProtoMessage() {}
 
-func (x *Repository) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[2]
+func (x *KeyCollision) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -192,58 +936,59 @@ func (x *Repository) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Repository.ProtoReflect.Descriptor instead.
-func (*Repository) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use KeyCollision.ProtoReflect.Descriptor instead.
+func (*KeyCollision) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Repository) GetRepositoryURL() string {
+func (x *KeyCollision) GetSecretName() *NamespacedName {
 	if x != nil {
-		return x.RepositoryURL
+		return x.SecretName
 	}
-	return ""
+	return nil
 }
 
-func (x *Repository) GetAuthentication() *Authentication {
+func (x *KeyCollision) GetKey() string {
 	if x != nil {
-		return x.Authentication
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-func (x *Repository) GetSecretName() *NamespacedName {
+func (x *KeyCollision) GetEntries() []string {
 	if x != nil {
-		return x.SecretName
+		return x.Entries
 	}
 	return nil
 }
 
-type GpgKeyReference struct {
+type TemplateError struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GpgKeyRef    string `protobuf:"bytes,1,opt,name=gpgKeyRef,proto3" json:"gpgKeyRef,omitempty"`
-	GpgKeyRefKey string `protobuf:"bytes,2,opt,name=gpgKeyRefKey,proto3" json:"gpgKeyRefKey,omitempty"`
+	SecretName *NamespacedName `protobuf:"bytes,1,opt,name=secretName,proto3" json:"secretName,omitempty"`
+	Key        string          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Message    string          `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *GpgKeyReference) Reset() {
-	*x = GpgKeyReference{}
+func (x *TemplateError) Reset() {
+	*x = TemplateError{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[3]
+		mi := &file_gopass_repository_repository_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GpgKeyReference) String() string {
+func (x *TemplateError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GpgKeyReference) ProtoMessage() {}
+func (*TemplateError) ProtoMessage() {}
 
-func (x *GpgKeyReference) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[3]
+func (x *TemplateError) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -254,51 +999,58 @@ func (x *GpgKeyReference) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GpgKeyReference.ProtoReflect.Descriptor instead.
-func (*GpgKeyReference) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use TemplateError.ProtoReflect.Descriptor instead.
+func (*TemplateError) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GpgKeyReference) GetGpgKeyRef() string {
+func (x *TemplateError) GetSecretName() *NamespacedName {
 	if x != nil {
-		return x.GpgKeyRef
+		return x.SecretName
+	}
+	return nil
+}
+
+func (x *TemplateError) GetKey() string {
+	if x != nil {
+		return x.Key
 	}
 	return ""
 }
 
-func (x *GpgKeyReference) GetGpgKeyRefKey() string {
+func (x *TemplateError) GetMessage() string {
 	if x != nil {
-		return x.GpgKeyRefKey
+		return x.Message
 	}
 	return ""
 }
 
-type RepositoryInitialization struct {
+type StaleKey struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Repository      *Repository      `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
-	GpgKeyReference *GpgKeyReference `protobuf:"bytes,2,opt,name=gpgKeyReference,proto3" json:"gpgKeyReference,omitempty"`
+	SecretName *NamespacedName `protobuf:"bytes,1,opt,name=secretName,proto3" json:"secretName,omitempty"`
+	Key        string          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 }
 
-func (x *RepositoryInitialization) Reset() {
-	*x = RepositoryInitialization{}
+func (x *StaleKey) Reset() {
+	*x = StaleKey{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[4]
+		mi := &file_gopass_repository_repository_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RepositoryInitialization) String() string {
+func (x *StaleKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RepositoryInitialization) ProtoMessage() {}
+func (*StaleKey) ProtoMessage() {}
 
-func (x *RepositoryInitialization) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[4]
+func (x *StaleKey) ProtoReflect() protoreflect.Message {
+	mi := &file_gopass_repository_repository_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -309,23 +1061,23 @@ func (x *RepositoryInitialization) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RepositoryInitialization.ProtoReflect.Descriptor instead.
-func (*RepositoryInitialization) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use StaleKey.ProtoReflect.Descriptor instead.
+func (*StaleKey) Descriptor() ([]byte, []int) {
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *RepositoryInitialization) GetRepository() *Repository {
+func (x *StaleKey) GetSecretName() *NamespacedName {
 	if x != nil {
-		return x.Repository
+		return x.SecretName
 	}
 	return nil
 }
 
-func (x *RepositoryInitialization) GetGpgKeyReference() *GpgKeyReference {
+func (x *StaleKey) GetKey() string {
 	if x != nil {
-		return x.GpgKeyReference
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
 type RepositoryResponse struct {
@@ -333,14 +1085,22 @@ type RepositoryResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Successful   bool   `protobuf:"varint,1,opt,name=successful,proto3" json:"successful,omitempty"`
-	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	Successful     bool              `protobuf:"varint,1,opt,name=successful,proto3" json:"successful,omitempty"`
+	ErrorMessage   string            `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	CommitHash     string            `protobuf:"bytes,3,opt,name=commitHash,proto3" json:"commitHash,omitempty"`
+	SyncedEntries  int32             `protobuf:"varint,4,opt,name=syncedEntries,proto3" json:"syncedEntries,omitempty"`
+	Collisions     []*KeyCollision   `protobuf:"bytes,5,rep,name=collisions,proto3" json:"collisions,omitempty"`
+	TemplateErrors []*TemplateError  `protobuf:"bytes,6,rep,name=templateErrors,proto3" json:"templateErrors,omitempty"`
+	StaleKeys      []*StaleKey       `protobuf:"bytes,7,rep,name=staleKeys,proto3" json:"staleKeys,omitempty"`
+	Updated        []*NamespacedName `protobuf:"bytes,8,rep,name=updated,proto3" json:"updated,omitempty"`
+	Unchanged      []*NamespacedName `protobuf:"bytes,9,rep,name=unchanged,proto3" json:"unchanged,omitempty"`
+	Untargeted     []*NamespacedName `protobuf:"bytes,10,rep,name=untargeted,proto3" json:"untargeted,omitempty"`
 }
 
 func (x *RepositoryResponse) Reset() {
 	*x = RepositoryResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[5]
+		mi := &file_gopass_repository_repository_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -353,7 +1113,7 @@ func (x *RepositoryResponse) String() string {
 func (*RepositoryResponse) ProtoMessage() {}
 
 func (x *RepositoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[5]
+	mi := &file_gopass_repository_repository_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +1126,7 @@ func (x *RepositoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RepositoryResponse.ProtoReflect.Descriptor instead.
 func (*RepositoryResponse) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{5}
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RepositoryResponse) GetSuccessful() bool {
@@ -383,6 +1143,62 @@ func (x *RepositoryResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *RepositoryResponse) GetCommitHash() string {
+	if x != nil {
+		return x.CommitHash
+	}
+	return ""
+}
+
+func (x *RepositoryResponse) GetSyncedEntries() int32 {
+	if x != nil {
+		return x.SyncedEntries
+	}
+	return 0
+}
+
+func (x *RepositoryResponse) GetCollisions() []*KeyCollision {
+	if x != nil {
+		return x.Collisions
+	}
+	return nil
+}
+
+func (x *RepositoryResponse) GetTemplateErrors() []*TemplateError {
+	if x != nil {
+		return x.TemplateErrors
+	}
+	return nil
+}
+
+func (x *RepositoryResponse) GetStaleKeys() []*StaleKey {
+	if x != nil {
+		return x.StaleKeys
+	}
+	return nil
+}
+
+func (x *RepositoryResponse) GetUpdated() []*NamespacedName {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+func (x *RepositoryResponse) GetUnchanged() []*NamespacedName {
+	if x != nil {
+		return x.Unchanged
+	}
+	return nil
+}
+
+func (x *RepositoryResponse) GetUntargeted() []*NamespacedName {
+	if x != nil {
+		return x.Untargeted
+	}
+	return nil
+}
+
 type Secret struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -395,7 +1211,7 @@ type Secret struct {
 func (x *Secret) Reset() {
 	*x = Secret{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[6]
+		mi := &file_gopass_repository_repository_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -408,7 +1224,7 @@ func (x *Secret) String() string {
 func (*Secret) ProtoMessage() {}
 
 func (x *Secret) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[6]
+	mi := &file_gopass_repository_repository_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,7 +1237,7 @@ func (x *Secret) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Secret.ProtoReflect.Descriptor instead.
 func (*Secret) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{6}
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Secret) GetName() string {
@@ -449,7 +1265,7 @@ type SecretList struct {
 func (x *SecretList) Reset() {
 	*x = SecretList{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gopass_repository_repository_proto_msgTypes[7]
+		mi := &file_gopass_repository_repository_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -462,7 +1278,7 @@ func (x *SecretList) String() string {
 func (*SecretList) ProtoMessage() {}
 
 func (x *SecretList) ProtoReflect() protoreflect.Message {
-	mi := &file_gopass_repository_repository_proto_msgTypes[7]
+	mi := &file_gopass_repository_repository_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +1291,7 @@ func (x *SecretList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SecretList.ProtoReflect.Descriptor instead.
 func (*SecretList) Descriptor() ([]byte, []int) {
-	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{7}
+	return file_gopass_repository_repository_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *SecretList) GetSecrets() []*Secret {
@@ -491,89 +1307,298 @@ var file_gopass_repository_repository_proto_rawDesc = []byte{
 	0x0a, 0x22, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
 	0x6f, 0x72, 0x79, 0x2f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70,
-	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x86, 0x01, 0x0a, 0x0e, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
-	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e,
-	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65,
-	0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52,
-	0x65, 0x66, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79,
-	0x22, 0x42, 0x0a, 0x0e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0xc0, 0x01, 0x0a, 0x0a, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
-	0x6f, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
-	0x79, 0x55, 0x52, 0x4c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x55, 0x52, 0x4c, 0x12, 0x49, 0x0a, 0x0e, 0x61, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73,
-	0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
-	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d,
-	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0a, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x53, 0x0a, 0x0f, 0x47, 0x70, 0x67, 0x4b, 0x65,
-	0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x67, 0x70,
-	0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67,
-	0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x12, 0x22, 0x0a, 0x0c, 0x67, 0x70, 0x67, 0x4b,
-	0x65, 0x79, 0x52, 0x65, 0x66, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x4b, 0x65, 0x79, 0x22, 0xa7, 0x01, 0x0a,
-	0x18, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x69, 0x74, 0x69,
-	0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0a, 0x72, 0x65, 0x70,
-	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
-	0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
-	0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x0a, 0x72, 0x65,
-	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x4c, 0x0a, 0x0f, 0x67, 0x70, 0x67, 0x4b,
-	0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x22, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73,
-	0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x47, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65,
-	0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0f, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66,
-	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x58, 0x0a, 0x12, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69,
-	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x66, 0x75, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x66, 0x75, 0x6c, 0x12, 0x22, 0x0a, 0x0c,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x38, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x41, 0x0a, 0x0a, 0x53, 0x65,
-	0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x70, 0x61,
-	0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x53, 0x65,
-	0x63, 0x72, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x32, 0x93, 0x03,
-	0x0a, 0x11, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x6c, 0x0a, 0x14, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2b, 0x2e, 0x67, 0x6f,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x50, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0xca, 0x03, 0x0a, 0x0e, 0x41, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x52, 0x65, 0x66, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66,
+	0x12, 0x22, 0x0a, 0x0c, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x4b, 0x65, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x52, 0x65,
+	0x66, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x13, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x50, 0x61,
+	0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x13, 0x73, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72,
+	0x61, 0x73, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x49, 0x0a, 0x0b, 0x63, 0x61, 0x42, 0x75, 0x6e, 0x64,
+	0x6c, 0x65, 0x52, 0x65, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x6f,
 	0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e,
-	0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61,
-	0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x52, 0x0b, 0x63, 0x61, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52, 0x65,
+	0x66, 0x12, 0x4d, 0x0a, 0x0d, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52,
+	0x65, 0x66, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x52, 0x0d, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x66,
+	0x12, 0x34, 0x0a, 0x15, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x49, 0x67, 0x6e, 0x6f,
+	0x72, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x15, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x48,
+	0x6f, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x42, 0x0a, 0x0e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0a, 0x4b, 0x65,
+	0x79, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x14,
+	0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x22, 0x67, 0x0a, 0x13, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79,
+	0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x22, 0xe0, 0x05,
+	0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x35,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67,
+	0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79,
+	0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x18, 0x0a,
+	0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x4d, 0x61, 0x70,
+	0x70, 0x69, 0x6e, 0x67, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x12, 0x4c, 0x0a, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f,
+	0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x0a,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x43,
+	0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b,
+	0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x52, 0x0a, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x3c, 0x0a, 0x0e, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x3e, 0x0a, 0x10, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x8d, 0x08, 0x0a, 0x0a, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x24, 0x0a, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x55, 0x52, 0x4c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x55, 0x52, 0x4c, 0x12, 0x49, 0x0a, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x41, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67,
+	0x12, 0x28, 0x0a, 0x0f, 0x6b, 0x65, 0x79, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6b, 0x65, 0x79, 0x4e, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x28, 0x0a, 0x0f, 0x63, 0x6f,
+	0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x4a, 0x0a, 0x09, 0x74, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e,
+	0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x75, 0x6e, 0x65, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x75,
+	0x6e, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x41, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
 	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70,
-	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x5a, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x73,
-	0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72,
-	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69,
-	0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65,
-	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
-	0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5c, 0x0a,
-	0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x6c, 0x6c, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
-	0x72, 0x64, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70,
-	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
-	0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x50, 0x0a, 0x0b, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e,
+	0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x10, 0x0a,
+	0x03, 0x72, 0x65, 0x66, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12,
+	0x30, 0x0a, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x61, 0x74, 0x68, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x26, 0x0a, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x1a, 0x3c, 0x0a, 0x0e, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x3e, 0x0a, 0x10, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xaa, 0x01, 0x0a, 0x05, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x55, 0x52, 0x4c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x55, 0x52, 0x4c,
+	0x12, 0x49, 0x0a, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x41, 0x75, 0x74,
+	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x61, 0x75, 0x74,
+	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x65, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x22, 0xb1, 0x01,
+	0x0a, 0x0f, 0x47, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x12,
+	0x22, 0x0a, 0x0c, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x4b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66,
+	0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x67, 0x70, 0x67, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68,
+	0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x67,
+	0x70, 0x67, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x66, 0x12,
+	0x30, 0x0a, 0x13, 0x67, 0x70, 0x67, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x66, 0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x67, 0x70,
+	0x67, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x66, 0x4b, 0x65,
+	0x79, 0x22, 0xa7, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3d,
+	0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f,
 	0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
-	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x0c, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x79, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x4c, 0x0a,
+	0x0f, 0x67, 0x70, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f,
+	0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x47, 0x70, 0x67, 0x4b, 0x65,
+	0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0f, 0x67, 0x70, 0x67, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x7d, 0x0a, 0x0c, 0x4b,
+	0x65, 0x79, 0x43, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61,
+	0x6d, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x7e, 0x0a, 0x0d, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x41, 0x0a, 0x0a, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61,
+	0x6d, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x5f, 0x0a, 0x08, 0x53, 0x74,
+	0x61, 0x6c, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x41, 0x0a, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70,
+	0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0a, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0xa5, 0x04, 0x0a, 0x12,
+	0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x66, 0x75, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x66,
+	0x75, 0x6c, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x48, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x79, 0x6e, 0x63, 0x65, 0x64,
+	0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73,
+	0x79, 0x6e, 0x63, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x48, 0x0a,
+	0x0e, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x0e, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x39, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x6c, 0x65,
+	0x4b, 0x65, 0x79, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70,
+	0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x53,
+	0x74, 0x61, 0x6c, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x09, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x4b, 0x65,
+	0x79, 0x73, 0x12, 0x3b, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12,
+	0x3f, 0x0a, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x12, 0x41, 0x0a, 0x0a, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x65, 0x64, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0a, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x65, 0x64, 0x22, 0x38, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x41, 0x0a,
+	0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79,
+	0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x32, 0xef, 0x03, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6c, 0x0a, 0x14, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2b,
+	0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x25, 0x2e, 0x67, 0x6f,
 	0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e,
-	0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70,
+	0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73,
+	0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73,
+	0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x5c, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x6c, 0x6c, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f,
+	0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56,
+	0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e,
+	0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x10, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x70,
 	0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52,
-	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x42, 0x21, 0x5a, 0x1f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x2f, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x1a, 0x25, 0x2e, 0x67, 0x6f, 0x70, 0x61,
+	0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x52, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x42, 0x21, 0x5a, 0x1f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x2f, 0x67, 0x6f, 0x70, 0x61, 0x73, 0x73, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x6f, 0x72, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -588,36 +1613,75 @@ func file_gopass_repository_repository_proto_rawDescGZIP() []byte {
 	return file_gopass_repository_repository_proto_rawDescData
 }
 
-var file_gopass_repository_repository_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_gopass_repository_repository_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_gopass_repository_repository_proto_goTypes = []interface{}{
-	(*Authentication)(nil),           // 0: gopass_repository.Authentication
-	(*NamespacedName)(nil),           // 1: gopass_repository.NamespacedName
-	(*Repository)(nil),               // 2: gopass_repository.Repository
-	(*GpgKeyReference)(nil),          // 3: gopass_repository.GpgKeyReference
-	(*RepositoryInitialization)(nil), // 4: gopass_repository.RepositoryInitialization
-	(*RepositoryResponse)(nil),       // 5: gopass_repository.RepositoryResponse
-	(*Secret)(nil),                   // 6: gopass_repository.Secret
-	(*SecretList)(nil),               // 7: gopass_repository.SecretList
+	(*ResourceKeyReference)(nil),     // 0: gopass_repository.ResourceKeyReference
+	(*Authentication)(nil),           // 1: gopass_repository.Authentication
+	(*NamespacedName)(nil),           // 2: gopass_repository.NamespacedName
+	(*KeyMapping)(nil),               // 3: gopass_repository.KeyMapping
+	(*RegistryCredentials)(nil),      // 4: gopass_repository.RegistryCredentials
+	(*SecretTarget)(nil),             // 5: gopass_repository.SecretTarget
+	(*Repository)(nil),               // 6: gopass_repository.Repository
+	(*Mount)(nil),                    // 7: gopass_repository.Mount
+	(*GpgKeyReference)(nil),          // 8: gopass_repository.GpgKeyReference
+	(*RepositoryInitialization)(nil), // 9: gopass_repository.RepositoryInitialization
+	(*KeyCollision)(nil),             // 10: gopass_repository.KeyCollision
+	(*TemplateError)(nil),            // 11: gopass_repository.TemplateError
+	(*StaleKey)(nil),                 // 12: gopass_repository.StaleKey
+	(*RepositoryResponse)(nil),       // 13: gopass_repository.RepositoryResponse
+	(*Secret)(nil),                   // 14: gopass_repository.Secret
+	(*SecretList)(nil),               // 15: gopass_repository.SecretList
+	nil,                              // 16: gopass_repository.SecretTarget.TemplatesEntry
+	nil,                              // 17: gopass_repository.SecretTarget.LabelsEntry
+	nil,                              // 18: gopass_repository.SecretTarget.AnnotationsEntry
+	nil,                              // 19: gopass_repository.Repository.TemplatesEntry
+	nil,                              // 20: gopass_repository.Repository.LabelsEntry
+	nil,                              // 21: gopass_repository.Repository.AnnotationsEntry
 }
 var file_gopass_repository_repository_proto_depIdxs = []int32{
-	0, // 0: gopass_repository.Repository.authentication:type_name -> gopass_repository.Authentication
-	1, // 1: gopass_repository.Repository.SecretName:type_name -> gopass_repository.NamespacedName
-	2, // 2: gopass_repository.RepositoryInitialization.repository:type_name -> gopass_repository.Repository
-	3, // 3: gopass_repository.RepositoryInitialization.gpgKeyReference:type_name -> gopass_repository.GpgKeyReference
-	6, // 4: gopass_repository.SecretList.secrets:type_name -> gopass_repository.Secret
-	4, // 5: gopass_repository.RepositoryService.InitializeRepository:input_type -> gopass_repository.RepositoryInitialization
-	2, // 6: gopass_repository.RepositoryService.UpdateRepository:input_type -> gopass_repository.Repository
-	2, // 7: gopass_repository.RepositoryService.UpdateAllPasswords:input_type -> gopass_repository.Repository
-	2, // 8: gopass_repository.RepositoryService.DeleteSecret:input_type -> gopass_repository.Repository
-	5, // 9: gopass_repository.RepositoryService.InitializeRepository:output_type -> gopass_repository.RepositoryResponse
-	5, // 10: gopass_repository.RepositoryService.UpdateRepository:output_type -> gopass_repository.RepositoryResponse
-	5, // 11: gopass_repository.RepositoryService.UpdateAllPasswords:output_type -> gopass_repository.RepositoryResponse
-	5, // 12: gopass_repository.RepositoryService.DeleteSecret:output_type -> gopass_repository.RepositoryResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0,  // 0: gopass_repository.Authentication.caBundleRef:type_name -> gopass_repository.ResourceKeyReference
+	0,  // 1: gopass_repository.Authentication.knownHostsRef:type_name -> gopass_repository.ResourceKeyReference
+	2,  // 2: gopass_repository.SecretTarget.name:type_name -> gopass_repository.NamespacedName
+	3,  // 3: gopass_repository.SecretTarget.data:type_name -> gopass_repository.KeyMapping
+	16, // 4: gopass_repository.SecretTarget.templates:type_name -> gopass_repository.SecretTarget.TemplatesEntry
+	4,  // 5: gopass_repository.SecretTarget.registries:type_name -> gopass_repository.RegistryCredentials
+	17, // 6: gopass_repository.SecretTarget.labels:type_name -> gopass_repository.SecretTarget.LabelsEntry
+	18, // 7: gopass_repository.SecretTarget.annotations:type_name -> gopass_repository.SecretTarget.AnnotationsEntry
+	1,  // 8: gopass_repository.Repository.authentication:type_name -> gopass_repository.Authentication
+	2,  // 9: gopass_repository.Repository.SecretName:type_name -> gopass_repository.NamespacedName
+	5,  // 10: gopass_repository.Repository.secrets:type_name -> gopass_repository.SecretTarget
+	19, // 11: gopass_repository.Repository.templates:type_name -> gopass_repository.Repository.TemplatesEntry
+	20, // 12: gopass_repository.Repository.labels:type_name -> gopass_repository.Repository.LabelsEntry
+	21, // 13: gopass_repository.Repository.annotations:type_name -> gopass_repository.Repository.AnnotationsEntry
+	7,  // 14: gopass_repository.Repository.mounts:type_name -> gopass_repository.Mount
+	1,  // 15: gopass_repository.Mount.authentication:type_name -> gopass_repository.Authentication
+	6,  // 16: gopass_repository.RepositoryInitialization.repository:type_name -> gopass_repository.Repository
+	8,  // 17: gopass_repository.RepositoryInitialization.gpgKeyReference:type_name -> gopass_repository.GpgKeyReference
+	2,  // 18: gopass_repository.KeyCollision.secretName:type_name -> gopass_repository.NamespacedName
+	2,  // 19: gopass_repository.TemplateError.secretName:type_name -> gopass_repository.NamespacedName
+	2,  // 20: gopass_repository.StaleKey.secretName:type_name -> gopass_repository.NamespacedName
+	10, // 21: gopass_repository.RepositoryResponse.collisions:type_name -> gopass_repository.KeyCollision
+	11, // 22: gopass_repository.RepositoryResponse.templateErrors:type_name -> gopass_repository.TemplateError
+	12, // 23: gopass_repository.RepositoryResponse.staleKeys:type_name -> gopass_repository.StaleKey
+	2,  // 24: gopass_repository.RepositoryResponse.updated:type_name -> gopass_repository.NamespacedName
+	2,  // 25: gopass_repository.RepositoryResponse.unchanged:type_name -> gopass_repository.NamespacedName
+	2,  // 26: gopass_repository.RepositoryResponse.untargeted:type_name -> gopass_repository.NamespacedName
+	14, // 27: gopass_repository.SecretList.secrets:type_name -> gopass_repository.Secret
+	9,  // 28: gopass_repository.RepositoryService.InitializeRepository:input_type -> gopass_repository.RepositoryInitialization
+	6,  // 29: gopass_repository.RepositoryService.UpdateRepository:input_type -> gopass_repository.Repository
+	6,  // 30: gopass_repository.RepositoryService.UpdateAllPasswords:input_type -> gopass_repository.Repository
+	6,  // 31: gopass_repository.RepositoryService.DeleteSecret:input_type -> gopass_repository.Repository
+	6,  // 32: gopass_repository.RepositoryService.RemoveRepository:input_type -> gopass_repository.Repository
+	13, // 33: gopass_repository.RepositoryService.InitializeRepository:output_type -> gopass_repository.RepositoryResponse
+	13, // 34: gopass_repository.RepositoryService.UpdateRepository:output_type -> gopass_repository.RepositoryResponse
+	13, // 35: gopass_repository.RepositoryService.UpdateAllPasswords:output_type -> gopass_repository.RepositoryResponse
+	13, // 36: gopass_repository.RepositoryService.DeleteSecret:output_type -> gopass_repository.RepositoryResponse
+	13, // 37: gopass_repository.RepositoryService.RemoveRepository:output_type -> gopass_repository.RepositoryResponse
+	33, // [33:38] is the sub-list for method output_type
+	28, // [28:33] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_gopass_repository_repository_proto_init() }
@@ -627,7 +1691,7 @@ func file_gopass_repository_repository_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_gopass_repository_repository_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Authentication); i {
+			switch v := v.(*ResourceKeyReference); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -639,7 +1703,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NamespacedName); i {
+			switch v := v.(*Authentication); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -651,7 +1715,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Repository); i {
+			switch v := v.(*NamespacedName); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -663,7 +1727,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GpgKeyReference); i {
+			switch v := v.(*KeyMapping); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -675,7 +1739,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RepositoryInitialization); i {
+			switch v := v.(*RegistryCredentials); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -687,7 +1751,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RepositoryResponse); i {
+			switch v := v.(*SecretTarget); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -699,7 +1763,7 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Secret); i {
+			switch v := v.(*Repository); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -711,6 +1775,102 @@ func file_gopass_repository_repository_proto_init() {
 			}
 		}
 		file_gopass_repository_repository_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GpgKeyReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RepositoryInitialization); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyCollision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StaleKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RepositoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Secret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gopass_repository_repository_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretList); i {
 			case 0:
 				return &v.state
@@ -729,7 +1889,7 @@ func file_gopass_repository_repository_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gopass_repository_repository_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -759,6 +1919,7 @@ type RepositoryServiceClient interface {
 	UpdateRepository(ctx context.Context, in *Repository, opts ...grpc.CallOption) (*RepositoryResponse, error)
 	UpdateAllPasswords(ctx context.Context, in *Repository, opts ...grpc.CallOption) (*RepositoryResponse, error)
 	DeleteSecret(ctx context.Context, in *Repository, opts ...grpc.CallOption) (*RepositoryResponse, error)
+	RemoveRepository(ctx context.Context, in *Repository, opts ...grpc.CallOption) (*RepositoryResponse, error)
 }
 
 type repositoryServiceClient struct {
@@ -805,12 +1966,22 @@ func (c *repositoryServiceClient) DeleteSecret(ctx context.Context, in *Reposito
 	return out, nil
 }
 
+func (c *repositoryServiceClient) RemoveRepository(ctx context.Context, in *Repository, opts ...grpc.CallOption) (*RepositoryResponse, error) {
+	out := new(RepositoryResponse)
+	err := c.cc.Invoke(ctx, "/gopass_repository.RepositoryService/RemoveRepository", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepositoryServiceServer is the server API for RepositoryService service.
 type RepositoryServiceServer interface {
 	InitializeRepository(context.Context, *RepositoryInitialization) (*RepositoryResponse, error)
 	UpdateRepository(context.Context, *Repository) (*RepositoryResponse, error)
 	UpdateAllPasswords(context.Context, *Repository) (*RepositoryResponse, error)
 	DeleteSecret(context.Context, *Repository) (*RepositoryResponse, error)
+	RemoveRepository(context.Context, *Repository) (*RepositoryResponse, error)
 }
 
 // UnimplementedRepositoryServiceServer can be embedded to have forward compatible implementations.
@@ -829,6 +2000,9 @@ func (*UnimplementedRepositoryServiceServer) UpdateAllPasswords(context.Context,
 func (*UnimplementedRepositoryServiceServer) DeleteSecret(context.Context, *Repository) (*RepositoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecret not implemented")
 }
+func (*UnimplementedRepositoryServiceServer) RemoveRepository(context.Context, *Repository) (*RepositoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRepository not implemented")
+}
 
 func RegisterRepositoryServiceServer(s *grpc.Server, srv RepositoryServiceServer) {
 	s.RegisterService(&_RepositoryService_serviceDesc, srv)
@@ -906,6 +2080,24 @@ func _RepositoryService_DeleteSecret_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepositoryService_RemoveRepository_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Repository)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).RemoveRepository(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gopass_repository.RepositoryService/RemoveRepository",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).RemoveRepository(ctx, req.(*Repository))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RepositoryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "gopass_repository.RepositoryService",
 	HandlerType: (*RepositoryServiceServer)(nil),
@@ -926,6 +2118,10 @@ var _RepositoryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteSecret",
 			Handler:    _RepositoryService_DeleteSecret_Handler,
 		},
+		{
+			MethodName: "RemoveRepository",
+			Handler:    _RepositoryService_RemoveRepository_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gopass_repository/repository.proto",